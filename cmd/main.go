@@ -1,60 +1,242 @@
 package main
 
 import (
+	"context"
 	"database/sql"
+	"errors"
+	"flag"
+	"fmt"
 	"net/http"
 	"os"
+	"os/signal"
+	"syscall"
+	"time"
 
 	_ "github.com/lib/pq"
 	httpSwagger "github.com/swaggo/http-swagger"
     _ "github.com/Black-tag/productAPI/docs"
 
 	"github.com/Black-tag/productAPI/internal/api"
+	"github.com/Black-tag/productAPI/internal/config"
 	"github.com/Black-tag/productAPI/internal/database"
+	"github.com/Black-tag/productAPI/internal/metrics"
 	"github.com/Black-tag/productAPI/internal/middleware"
+	"github.com/Black-tag/productAPI/internal/permissions"
+	"github.com/Black-tag/productAPI/internal/storage"
+	"github.com/Black-tag/productAPI/internal/tracing"
 
 	"github.com/Black-tag/productAPI/internal/logger"
+	"go.uber.org/zap"
 )
 
+// imageUploadRoute is exempt from the request body limit, the upload
+// handler caps image bodies itself.
+const imageUploadRoute = "POST /api/v1/product/{productID}/images"
+
 func main() {
 
 	logger.Init()
 	defer logger.Log.Sync()
 
-	dbURL := os.Getenv("DB_URL")
-	if dbURL == "" {
-		logger.Log.Fatal("DB_URL env variable not set")
+	configFile := flag.String("config", os.Getenv("CONFIG_FILE"), "optional YAML config file")
+	envFile := flag.String("env-file", ".env", "env file, skipped when missing")
+	flag.Parse()
+
+	conf, err := config.Load(*configFile, *envFile)
+	if flag.Arg(0) == "config" && flag.Arg(1) == "print" {
+		// print even an invalid config, it is what the server would refuse
+		if err := conf.Print(os.Stdout); err != nil {
+			logger.Log.Fatal(err.Error())
+		}
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "invalid config:\n"+err.Error())
+			os.Exit(1)
+		}
+		return
+	}
+	if flag.NArg() > 0 {
+		logger.Log.Fatal("unknown command, the only command is: config print")
+	}
+	if err != nil {
+		logger.Log.Fatal("invalid config", zap.Error(err))
+	}
+
+	db, err := sql.Open("postgres", conf.Database.URL)
+	if err != nil {
+		logger.Log.Fatal(err.Error())
 	}
+	dbQueries := database.New(tracing.WrapDB(db))
+	metrics.RegisterDB(db, "postgres")
 
-	db, err := sql.Open("postgres", dbURL)
+	store, err := storage.NewLocal(conf.Media.Dir, conf.Media.BaseURL)
 	if err != nil {
 		logger.Log.Fatal(err.Error())
 	}
-	dbQueries := database.New(db)
-	secret := os.Getenv("SECRET")
 
 	cfg := api.APIConfig{
-		DB:     dbQueries,
-		SECRET: secret,
+		DB:              dbQueries,
+		DBConn:          db,
+		SECRET:          conf.Auth.Secret,
+		AccessTokenTTL:  conf.Auth.AccessTokenTTL,
+		RefreshTokenTTL: conf.Auth.RefreshTokenTTL,
+		BcryptCost:      conf.Auth.BcryptCost,
+		RequireIfMatch:  conf.Products.RequireIfMatch,
+		TrashRetention:  conf.Products.TrashRetention,
+		Storage:         store,
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	shutdownTracing, err := tracing.Setup(ctx, conf.Tracing)
+	if err != nil {
+		logger.Log.Fatal("failed to set up tracing", zap.Error(err))
 	}
 
+	purgeDone := make(chan struct{})
+	go func() {
+		defer close(purgeDone)
+		cfg.RunTrashPurge(ctx, conf.Products.TrashPurgeInterval)
+	}()
+
 	mux := http.NewServeMux()
 
 
 	protected := middleware.Authenticate(cfg.SECRET, cfg.DB)
+	can := func(perm permissions.Permission, next http.HandlerFunc) http.Handler {
+		return protected(middleware.RequirePermission(perm)(next))
+	}
 
 	mux.HandleFunc("POST /api/v1/users", cfg.CreateUserHandler)
 	mux.HandleFunc("POST /api/v1/login", cfg.UserLoginHandler)
-	mux.Handle("POST /api/v1/product", protected(http.HandlerFunc(cfg.ProductCreationHandler)))
+	mux.HandleFunc("POST /api/v1/refresh", cfg.RefreshTokenHandler)
+	mux.HandleFunc("POST /api/v1/logout", cfg.LogoutHandler)
+	mux.Handle("POST /api/v1/logout-all", protected(http.HandlerFunc(cfg.LogoutAllHandler)))
+	mux.Handle("POST /api/v1/product", can(permissions.ProductCreate, cfg.ProductCreationHandler))
 	mux.HandleFunc("GET /api/v1/product", cfg.GetProductsHandler)
+	mux.HandleFunc("GET /api/v1/product/search", cfg.SearchProductsHandler)
+	mux.HandleFunc("GET /api/v1/product/{productID}", cfg.GetProductHandler)
+	mux.HandleFunc("GET /api/v1/product/by-sku/{sku}", cfg.GetProductBySKUHandler)
+	mux.Handle("GET /api/v1/product/{productID}/{resource}", subresources(map[string]http.Handler{
+		"prices": http.HandlerFunc(cfg.GetProductPriceHistoryHandler),
+		"stock":  http.HandlerFunc(cfg.GetStockLevelHandler),
+	}))
+	mux.Handle("POST /api/v1/product/{productID}/variants", protected(http.HandlerFunc(cfg.CreateVariantHandler)))
+	mux.Handle("PUT /api/v1/product/{productID}/variants/{variantID}", protected(http.HandlerFunc(cfg.UpdateVariantHandler)))
+	mux.Handle("DELETE /api/v1/product/{productID}/variants/{variantID}", protected(http.HandlerFunc(cfg.DeleteVariantHandler)))
+	mux.Handle(imageUploadRoute, protected(http.HandlerFunc(cfg.UploadProductImageHandler)))
+	mux.Handle("PUT /api/v1/product/{productID}/images/order", protected(http.HandlerFunc(cfg.ReorderProductImagesHandler)))
+	mux.Handle("DELETE /api/v1/product/{productID}/images/{imageID}", protected(http.HandlerFunc(cfg.DeleteProductImageHandler)))
+	mux.Handle("GET /api/v1/product/{productID}/stock/movements", can(permissions.InventoryManage, cfg.ListStockMovementsHandler))
+	mux.Handle("POST /api/v1/product/{productID}/stock/movements", can(permissions.InventoryManage, cfg.CreateStockMovementHandler))
+	mux.Handle("GET /api/v1/product/trash", protected(http.HandlerFunc(cfg.ListTrashHandler)))
+	mux.Handle("POST /api/v1/product/{productID}/restore", protected(http.HandlerFunc(cfg.RestoreProductHandler)))
 	mux.Handle("PUT /api/v1/product/{productID}", protected(http.HandlerFunc(cfg.UpdateProductsHandler)))
+	mux.Handle("PATCH /api/v1/product/{productID}", protected(http.HandlerFunc(cfg.PatchProductHandler)))
 	mux.Handle("DELETE /api/v1/product/{productID}", protected(http.HandlerFunc(cfg.DeleteProductHandler)))
+	mux.Handle("GET /api/v1/admin/users", can(permissions.UserManage, cfg.ListUsersHandler))
+	mux.Handle("GET /api/v1/admin/users/{userID}", can(permissions.UserManage, cfg.GetUserHandler))
+	mux.Handle("PUT /api/v1/admin/users/{userID}/role", can(permissions.UserManage, cfg.UpdateUserRoleHandler))
+	mux.Handle("POST /api/v1/admin/users/{userID}/disable", can(permissions.UserManage, cfg.DisableUserHandler))
+	mux.Handle("POST /api/v1/admin/users/{userID}/enable", can(permissions.UserManage, cfg.EnableUserHandler))
+	mux.Handle("DELETE /api/v1/admin/users/{userID}", can(permissions.UserManage, cfg.DeleteUserHandler))
+	mux.Handle("GET /api/v1/admin/roles", can(permissions.UserManage, cfg.ListRolesHandler))
+	mux.Handle("DELETE /api/v1/admin/products/{productID}", can(permissions.ProductPurge, cfg.PurgeProductHandler))
+	mux.HandleFunc("GET /api/v1/tags", cfg.ListTagsHandler)
+	mux.HandleFunc("GET /api/v1/categories", cfg.ListCategoriesHandler)
+	mux.HandleFunc("GET /api/v1/categories/{categoryID}", cfg.GetCategoryHandler)
+	mux.Handle("POST /api/v1/admin/categories", can(permissions.CategoryManage, cfg.CreateCategoryHandler))
+	mux.Handle("PUT /api/v1/admin/categories/{categoryID}", can(permissions.CategoryManage, cfg.UpdateCategoryHandler))
+	mux.Handle("DELETE /api/v1/admin/categories/{categoryID}", can(permissions.CategoryManage, cfg.DeleteCategoryHandler))
+	mux.HandleFunc("GET /api/v1/warehouses", cfg.ListWarehousesHandler)
+	mux.Handle("POST /api/v1/admin/warehouses", can(permissions.WarehouseManage, cfg.CreateWarehouseHandler))
+	mux.Handle("PUT /api/v1/admin/warehouses/{warehouseID}", can(permissions.WarehouseManage, cfg.UpdateWarehouseHandler))
+	mux.Handle("DELETE /api/v1/admin/warehouses/{warehouseID}", can(permissions.WarehouseManage, cfg.DeleteWarehouseHandler))
+	mux.Handle("POST /api/v1/admin/stock-transfers", can(permissions.InventoryManage, cfg.CreateStockTransferHandler))
+	mux.HandleFunc("GET /api/v1/exchange-rates", cfg.ListExchangeRatesHandler)
+	mux.Handle("PUT /api/v1/admin/exchange-rates/{base}/{quote}", can(permissions.CurrencyManage, cfg.PutExchangeRateHandler))
+	mux.Handle("DELETE /api/v1/admin/exchange-rates/{base}/{quote}", can(permissions.CurrencyManage, cfg.DeleteExchangeRateHandler))
+	mux.Handle("GET /media/", http.StripPrefix("/media/", store))
 	mux.Handle("/swagger/", httpSwagger.WrapHandler)
+	mux.HandleFunc("GET /healthz", cfg.HealthzHandler)
+	mux.HandleFunc("GET /readyz", cfg.ReadyzHandler)
+	mux.Handle("GET /metrics", metrics.Handler())
 
-	logger.Log.Info("server starting on 8090")
-	if err := http.ListenAndServe(":8090", middleware.CorsMiddleware(mux)); err != nil {
-		logger.Log.Fatal("server not started")
-		return
+	srv := &http.Server{
+		Addr:              conf.Server.Addr,
+		Handler:           middleware.CorsMiddleware(conf.Server.CORSOrigin, tracing.Middleware(metrics.Middleware(middleware.LimitBody(int64(conf.Server.MaxBodyBytes), mux, imageUploadRoute)))),
+		ReadHeaderTimeout: conf.Server.ReadHeaderTimeout,
+		ReadTimeout:       conf.Server.ReadTimeout,
+		WriteTimeout:      conf.Server.WriteTimeout,
+		IdleTimeout:       conf.Server.IdleTimeout,
+		MaxHeaderBytes:    conf.Server.MaxHeaderBytes,
+	}
+
+	logger.Log.Info("server starting", zap.String("addr", conf.Server.Addr))
+	if err := serve(ctx, srv, conf.Server, cfg.StartDraining); err != nil {
+		logger.Log.Error("server stopped", zap.Error(err))
+	}
+
+	// also cancels ctx when the server failed rather than being signalled
+	stop()
+	<-purgeDone
+	flushCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	if err := shutdownTracing(flushCtx); err != nil {
+		logger.Log.Error("failed to flush traces", zap.Error(err))
+	}
+	cancel()
+	if err := db.Close(); err != nil {
+		logger.Log.Error("failed to close database", zap.Error(err))
 	}
+	logger.Log.Info("server stopped")
+}
+
+// serve runs srv until ctx is cancelled. It then calls draining, keeps
+// serving for the shutdown delay, stops accepting connections and waits up to
+// the shutdown timeout for in-flight requests. Requests still running after
+// that have their connections closed.
+func serve(ctx context.Context, srv *http.Server, conf config.ServerConfig, draining func()) error {
+	errc := make(chan error, 1)
+	go func() {
+		errc <- srv.ListenAndServe()
+	}()
+
+	select {
+	case err := <-errc:
+		return err
+	case <-ctx.Done():
+	}
+	draining()
+	logger.Log.Info("shutting down, draining requests", zap.Duration("delay", conf.ShutdownDelay), zap.Duration("timeout", conf.ShutdownTimeout))
+	select {
+	case err := <-errc:
+		return err
+	case <-time.After(conf.ShutdownDelay):
+	}
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), conf.ShutdownTimeout)
+	defer cancel()
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		srv.Close()
+		return fmt.Errorf("drain: %w", err)
+	}
+	if err := <-errc; !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
 
+// subresources serves GET /api/v1/product/{productID}/{resource}. Product
+// subresources cannot be registered one pattern each, since
+// /api/v1/product/{productID}/prices and /api/v1/product/by-sku/{sku} would
+// both match /api/v1/product/by-sku/prices.
+func subresources(routes map[string]http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		handler, ok := routes[r.PathValue("resource")]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		handler.ServeHTTP(w, r)
+	})
 }
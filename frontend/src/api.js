@@ -23,6 +23,11 @@ export async function getProducts() {
   return res.json();
 }
 
+export async function searchProducts(query) {
+  const res = await fetch(`${BASE_URL}/product/search?q=${encodeURIComponent(query)}`);
+  return res.json();
+}
+
 export async function createProduct(data, token) {
   const res = await fetch(`${BASE_URL}/product`, {
     method: "POST",
@@ -19,17 +19,13 @@ export default function ProductList() {
       });
 
       if (!res.ok) {
-        if (res.status === 204) {
-          setProducts([]);
-          return;
-        }
         const errData = await res.json().catch(() => ({}));
         console.error("Error fetching products:", errData);
         return;
       }
 
       const data = await res.json();
-      setProducts(data);
+      setProducts(data.products);
     } catch (err) {
       console.error("Error fetching products:", err);
     }
@@ -71,12 +67,13 @@ export default function ProductList() {
     e.preventDefault();
     try {
       const res = await fetch(
-        `http://localhost:8090/api/v1/product/${editProduct.ID}`,
+        `http://localhost:8090/api/v1/product/${editProduct.id}`,
         {
           method: "PUT",
           headers: {
             "Content-Type": "application/json",
             Authorization: `Bearer ${token}`,
+            "If-Match": `"${editProduct.version}"`,
           },
           body: JSON.stringify(editProduct),
         }
@@ -96,12 +93,13 @@ export default function ProductList() {
   };
 
   // Delete product
-  const handleDelete = async (ID) => {
+  const handleDelete = async (product) => {
     try {
-      const res = await fetch(`http://localhost:8090/api/v1/product/${ID}`, {
+      const res = await fetch(`http://localhost:8090/api/v1/product/${product.id}`, {
         method: "DELETE",
         headers: {
           Authorization: `Bearer ${token}`,
+          "If-Match": `"${product.version}"`,
         },
       });
 
@@ -138,7 +136,7 @@ export default function ProductList() {
           placeholder="Price"
           value={newProduct.Price}
           onChange={(e) =>
-            setNewProduct({ ...newProduct, Price: parseFloat(e.target.value) })
+            setNewProduct({ ...newProduct, Price: e.target.value })
           }
           required
         />
@@ -162,39 +160,39 @@ export default function ProductList() {
             </tr>
           ) : (
             products.map((p) => (
-              <tr key={p.ID}>
-                <td>{p.ID}</td>
+              <tr key={p.id}>
+                <td>{p.id}</td>
                 <td>
-                  {editProduct && editProduct.ID === p.ID ? (
+                  {editProduct && editProduct.id === p.id ? (
                     <input
                       type="text"
-                      value={editProduct.Name}
+                      value={editProduct.name}
                       onChange={(e) =>
-                        setEditProduct({ ...editProduct, Name: e.target.value })
+                        setEditProduct({ ...editProduct, name: e.target.value })
                       }
                     />
                   ) : (
-                    p.Name
+                    p.name
                   )}
                 </td>
                 <td>
-                  {editProduct && editProduct.ID === p.ID ? (
+                  {editProduct && editProduct.id === p.id ? (
                     <input
                       type="number"
-                      value={editProduct.Price}
+                      value={editProduct.price.amount}
                       onChange={(e) =>
                         setEditProduct({
                           ...editProduct,
-                          Price: parseFloat(e.target.value),
+                          price: { ...editProduct.price, amount: e.target.value },
                         })
                       }
                     />
                   ) : (
-                    p.Price
+                    `${p.price.amount} ${p.price.currency}`
                   )}
                 </td>
                 <td>
-                  {editProduct && editProduct.ID === p.ID ? (
+                  {editProduct && editProduct.id === p.id ? (
                     <>
                       <button onClick={handleUpdate}>Save</button>
                       <button onClick={() => setEditProduct(null)}>Cancel</button>
@@ -202,7 +200,7 @@ export default function ProductList() {
                   ) : (
                     <>
                       <button onClick={() => setEditProduct(p)}>Edit</button>
-                      <button onClick={() => handleDelete(p.ID)}>Delete</button>
+                      <button onClick={() => handleDelete(p)}>Delete</button>
                     </>
                   )}
                 </td>
@@ -1 +1,383 @@
 package api
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/Black-tag/productAPI/internal/database"
+	"github.com/Black-tag/productAPI/internal/logger"
+	"github.com/Black-tag/productAPI/internal/models"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// @Summary List users
+// @Description admin can list all users page by page
+// @Tags admin
+// @Produce json
+// @Param limit query int false "page size (default 20, max 100)"
+// @Param offset query int false "number of users to skip"
+// @Success 200 {object} models.UserListResponse
+// @Failure 400 {object} string "Bad Request - Invalid input"
+// @Failure 401 {object} string "Unauthorized - Missing/invalid credentials"
+// @Failure 403 {object} string "Forbidden - Insufficient permissions"
+// @Failure 500 {object} string "Internal Server Error"
+// @Router /api/v1/admin/users [get]
+// @Security BearerAuth
+func (cfg *APIConfig) ListUsersHandler(w http.ResponseWriter, r *http.Request) {
+	logger.Log.Info("entered list users handler")
+
+	limit, offset, err := parsePagination(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	users, err := cfg.DB.ListUsers(r.Context(), database.ListUsersParams{
+		Limit:  limit,
+		Offset: offset,
+	})
+	if err != nil {
+		logger.Log.Error("failed to list users", zap.Error(err))
+		http.Error(w, "databse operation failed", http.StatusInternalServerError)
+		return
+	}
+	total, err := cfg.DB.CountUsers(r.Context())
+	if err != nil {
+		logger.Log.Error("failed to count users", zap.Error(err))
+		http.Error(w, "databse operation failed", http.StatusInternalServerError)
+		return
+	}
+
+	respPayload := models.UserListResponse{
+		Users:  make([]models.AdminUserResponse, 0, len(users)),
+		Total:  total,
+		Limit:  limit,
+		Offset: offset,
+	}
+	for _, user := range users {
+		respPayload.Users = append(respPayload.Users, adminUserResponse(user))
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(respPayload); err != nil {
+		http.Error(w, "failed to encode users", http.StatusInternalServerError)
+		return
+	}
+}
+
+// @Summary Get a user
+// @Description admin can view a single user by id
+// @Tags admin
+// @Produce json
+// @Param userID path string true "userID"
+// @Success 200 {object} models.AdminUserResponse
+// @Failure 400 {object} string "Bad Request - Invalid input"
+// @Failure 401 {object} string "Unauthorized - Missing/invalid credentials"
+// @Failure 403 {object} string "Forbidden - Insufficient permissions"
+// @Failure 404 {object} string "Not Found - Resource doesn't exist"
+// @Failure 500 {object} string "Internal Server Error"
+// @Router /api/v1/admin/users/{userID} [get]
+// @Security BearerAuth
+func (cfg *APIConfig) GetUserHandler(w http.ResponseWriter, r *http.Request) {
+	logger.Log.Info("entered get user handler")
+
+	userID, err := uuid.Parse(r.PathValue("userID"))
+	if err != nil {
+		http.Error(w, "Invalid user id", http.StatusBadRequest)
+		return
+	}
+
+	user, err := cfg.DB.GetUserByID(r.Context(), userID)
+	if errors.Is(err, sql.ErrNoRows) {
+		http.Error(w, "user not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		logger.Log.Error("failed to fetch user", zap.String("userID", userID.String()), zap.Error(err))
+		http.Error(w, "databse operation failed", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(adminUserResponse(user)); err != nil {
+		http.Error(w, "failed to encode user", http.StatusInternalServerError)
+		return
+	}
+}
+
+// @Summary Change a user's role
+// @Description admin can promote or demote a user. Admins cannot change their own role
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param userID path string true "userID"
+// @Param request body models.UpdateUserRoleRequest true "new role"
+// @Success 200 {object} models.AdminUserResponse
+// @Failure 400 {object} string "Bad Request - Invalid input"
+// @Failure 401 {object} string "Unauthorized - Missing/invalid credentials"
+// @Failure 403 {object} string "Forbidden - Insufficient permissions"
+// @Failure 404 {object} string "Not Found - Resource doesn't exist"
+// @Failure 500 {object} string "Internal Server Error"
+// @Router /api/v1/admin/users/{userID}/role [put]
+// @Security BearerAuth
+func (cfg *APIConfig) UpdateUserRoleHandler(w http.ResponseWriter, r *http.Request) {
+	logger.Log.Info("entered update user role handler")
+
+	userID, ok := cfg.targetUserID(w, r)
+	if !ok {
+		return
+	}
+
+	var req models.UpdateUserRoleRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+	if _, err := cfg.DB.GetRoleByName(r.Context(), req.Role); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			http.Error(w, "unknown role", http.StatusBadRequest)
+			return
+		}
+		logger.Log.Error("failed to fetch role", zap.String("role", req.Role), zap.Error(err))
+		http.Error(w, "databse operation failed", http.StatusInternalServerError)
+		return
+	}
+
+	user, err := cfg.DB.UpdateUserRole(r.Context(), database.UpdateUserRoleParams{
+		ID:   userID,
+		Role: req.Role,
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		http.Error(w, "user not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		logger.Log.Error("failed to update user role", zap.String("userID", userID.String()), zap.Error(err))
+		http.Error(w, "databse operation failed", http.StatusInternalServerError)
+		return
+	}
+	logger.Log.Info("user role changed",
+		zap.String("userID", userID.String()),
+		zap.String("role", user.Role),
+	)
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(adminUserResponse(user)); err != nil {
+		http.Error(w, "failed to encode user", http.StatusInternalServerError)
+		return
+	}
+}
+
+// @Summary Disable a user
+// @Description admin can disable an account. Disabled users cannot log in and all of their refresh tokens are revoked
+// @Tags admin
+// @Produce json
+// @Param userID path string true "userID"
+// @Success 200 {object} models.AdminUserResponse
+// @Failure 400 {object} string "Bad Request - Invalid input"
+// @Failure 401 {object} string "Unauthorized - Missing/invalid credentials"
+// @Failure 403 {object} string "Forbidden - Insufficient permissions"
+// @Failure 404 {object} string "Not Found - Resource doesn't exist"
+// @Failure 500 {object} string "Internal Server Error"
+// @Router /api/v1/admin/users/{userID}/disable [post]
+// @Security BearerAuth
+func (cfg *APIConfig) DisableUserHandler(w http.ResponseWriter, r *http.Request) {
+	logger.Log.Info("entered disable user handler")
+	cfg.setUserDisabled(w, r, true)
+}
+
+// @Summary Enable a user
+// @Description admin can re-enable a disabled account
+// @Tags admin
+// @Produce json
+// @Param userID path string true "userID"
+// @Success 200 {object} models.AdminUserResponse
+// @Failure 400 {object} string "Bad Request - Invalid input"
+// @Failure 401 {object} string "Unauthorized - Missing/invalid credentials"
+// @Failure 403 {object} string "Forbidden - Insufficient permissions"
+// @Failure 404 {object} string "Not Found - Resource doesn't exist"
+// @Failure 500 {object} string "Internal Server Error"
+// @Router /api/v1/admin/users/{userID}/enable [post]
+// @Security BearerAuth
+func (cfg *APIConfig) EnableUserHandler(w http.ResponseWriter, r *http.Request) {
+	logger.Log.Info("entered enable user handler")
+	cfg.setUserDisabled(w, r, false)
+}
+
+// @Summary Delete a user
+// @Description admin can delete a user together with their tokens. Users who still own products, including ones in the trash, cannot be deleted; disable them instead. Admins cannot delete themselves
+// @Tags admin
+// @Produce json
+// @Param userID path string true "userID"
+// @Success 204 {string} string "No content"
+// @Failure 400 {object} string "Bad Request - Invalid input"
+// @Failure 401 {object} string "Unauthorized - Missing/invalid credentials"
+// @Failure 403 {object} string "Forbidden - Insufficient permissions"
+// @Failure 404 {object} string "Not Found - Resource doesn't exist"
+// @Failure 409 {object} string "Conflict - user still owns products"
+// @Failure 500 {object} string "Internal Server Error"
+// @Router /api/v1/admin/users/{userID} [delete]
+// @Security BearerAuth
+func (cfg *APIConfig) DeleteUserHandler(w http.ResponseWriter, r *http.Request) {
+	logger.Log.Info("entered delete user handler")
+
+	userID, ok := cfg.targetUserID(w, r)
+	if !ok {
+		return
+	}
+
+	deleted, err := cfg.DB.DeleteUserByID(r.Context(), userID)
+	if isPQError(err, pqForeignKeyViolation) {
+		http.Error(w, "user still owns products, disable the user instead", http.StatusConflict)
+		return
+	}
+	if err != nil {
+		logger.Log.Error("failed to delete user", zap.String("userID", userID.String()), zap.Error(err))
+		http.Error(w, "databse deletion failed", http.StatusInternalServerError)
+		return
+	}
+	if deleted == 0 {
+		http.Error(w, "user not found", http.StatusNotFound)
+		return
+	}
+	logger.Log.Info("user deleted", zap.String("userID", userID.String()))
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// @Summary List roles
+// @Description admin can list the roles users can be assigned and the permissions each one grants
+// @Tags admin
+// @Produce json
+// @Success 200 {array} models.RoleResponse
+// @Failure 401 {object} string "Unauthorized - Missing/invalid credentials"
+// @Failure 403 {object} string "Forbidden - Insufficient permissions"
+// @Failure 500 {object} string "Internal Server Error"
+// @Router /api/v1/admin/roles [get]
+// @Security BearerAuth
+func (cfg *APIConfig) ListRolesHandler(w http.ResponseWriter, r *http.Request) {
+	logger.Log.Info("entered list roles handler")
+
+	rows, err := cfg.DB.ListRolePermissions(r.Context())
+	if err != nil {
+		logger.Log.Error("failed to list roles", zap.Error(err))
+		http.Error(w, "databse operation failed", http.StatusInternalServerError)
+		return
+	}
+
+	roles := []models.RoleResponse{}
+	for _, row := range rows {
+		if len(roles) == 0 || roles[len(roles)-1].Name != row.Name {
+			roles = append(roles, models.RoleResponse{
+				Name:        row.Name,
+				Description: row.Description,
+				Permissions: []string{},
+			})
+		}
+		if row.Permission.Valid {
+			current := &roles[len(roles)-1]
+			current.Permissions = append(current.Permissions, row.Permission.String)
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(roles); err != nil {
+		http.Error(w, "failed to encode roles", http.StatusInternalServerError)
+		return
+	}
+}
+
+func (cfg *APIConfig) setUserDisabled(w http.ResponseWriter, r *http.Request, disabled bool) {
+	userID, ok := cfg.targetUserID(w, r)
+	if !ok {
+		return
+	}
+
+	disabledAt := sql.NullTime{}
+	if disabled {
+		disabledAt = sql.NullTime{Time: time.Now(), Valid: true}
+	}
+
+	tx, err := cfg.DBConn.BeginTx(r.Context(), nil)
+	if err != nil {
+		logger.Log.Error("failed to begin transaction", zap.Error(err))
+		http.Error(w, "databse operation failed", http.StatusInternalServerError)
+		return
+	}
+	defer tx.Rollback()
+	qtx := cfg.txQueries(tx)
+
+	user, err := qtx.SetUserDisabledAt(r.Context(), database.SetUserDisabledAtParams{
+		ID:         userID,
+		DisabledAt: disabledAt,
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		http.Error(w, "user not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		logger.Log.Error("failed to update user", zap.String("userID", userID.String()), zap.Error(err))
+		http.Error(w, "databse operation failed", http.StatusInternalServerError)
+		return
+	}
+	if disabled {
+		if _, err := qtx.RevokeAllRefreshTokensForUser(r.Context(), userID); err != nil {
+			logger.Log.Error("failed to revoke refresh tokens", zap.String("userID", userID.String()), zap.Error(err))
+			http.Error(w, "databse operation failed", http.StatusInternalServerError)
+			return
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		logger.Log.Error("failed to commit user update", zap.Error(err))
+		http.Error(w, "databse operation failed", http.StatusInternalServerError)
+		return
+	}
+	logger.Log.Info("user disabled state changed",
+		zap.String("userID", userID.String()),
+		zap.Bool("disabled", disabled),
+	)
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(adminUserResponse(user)); err != nil {
+		http.Error(w, "failed to encode user", http.StatusInternalServerError)
+		return
+	}
+}
+
+// targetUserID parses the userID path value for endpoints that modify an
+// account and refuses to let admins act on themselves, so the last admin
+// cannot lock everyone out by accident.
+func (cfg *APIConfig) targetUserID(w http.ResponseWriter, r *http.Request) (uuid.UUID, bool) {
+	userID, err := uuid.Parse(r.PathValue("userID"))
+	if err != nil {
+		http.Error(w, "Invalid user id", http.StatusBadRequest)
+		return uuid.Nil, false
+	}
+	callerID, ok := r.Context().Value("userID").(uuid.UUID)
+	if !ok {
+		http.Error(w, "userID not in context", http.StatusUnauthorized)
+		return uuid.Nil, false
+	}
+	if callerID == userID {
+		http.Error(w, "admins cannot modify their own account", http.StatusForbidden)
+		return uuid.Nil, false
+	}
+	return userID, true
+}
+
+func adminUserResponse(user database.User) models.AdminUserResponse {
+	resp := models.AdminUserResponse{
+		ID:        user.ID,
+		Email:     user.Email,
+		Role:      user.Role,
+		CreatedAt: user.CreatedAt,
+		UpdatedAt: user.UpdatedAt,
+	}
+	if user.DisabledAt.Valid {
+		resp.DisabledAt = &user.DisabledAt.Time
+	}
+	return resp
+}
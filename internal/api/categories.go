@@ -0,0 +1,390 @@
+package api
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+
+	"github.com/Black-tag/productAPI/internal/database"
+	"github.com/Black-tag/productAPI/internal/logger"
+	"github.com/Black-tag/productAPI/internal/models"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// categoryTreeLockKey is the advisory lock taken by transactions that
+// compute category paths.
+const categoryTreeLockKey = 7_014_001
+
+var (
+	slugPattern = regexp.MustCompile(`^[a-z0-9]+(-[a-z0-9]+)*$`)
+
+	errUnknownCategory  = errors.New("unknown category")
+	errCategoryCycle    = errors.New("a category cannot be moved under itself or its descendants")
+	errCategoryNotFound = errors.New("category not found")
+)
+
+// @Summary List categories
+// @Description anyone can list every category, ordered by path so parents come before their children
+// @Tags categories
+// @Produce json
+// @Success 200 {array} models.CategoryResponse
+// @Failure 500 {object} string "Internal Server Error"
+// @Router /api/v1/categories [get]
+func (cfg *APIConfig) ListCategoriesHandler(w http.ResponseWriter, r *http.Request) {
+	logger.Log.Info("entered list categories handler")
+
+	categories, err := cfg.DB.ListCategories(r.Context())
+	if err != nil {
+		logger.Log.Error("failed to list categories", zap.Error(err))
+		http.Error(w, "databse operation failed", http.StatusInternalServerError)
+		return
+	}
+
+	respPayload := make([]models.CategoryResponse, 0, len(categories))
+	for _, category := range categories {
+		respPayload = append(respPayload, categoryResponse(category))
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(respPayload); err != nil {
+		http.Error(w, "failed to encode categories", http.StatusInternalServerError)
+		return
+	}
+}
+
+// @Summary Get a category
+// @Tags categories
+// @Produce json
+// @Param categoryID path string true "categoryID"
+// @Success 200 {object} models.CategoryResponse
+// @Failure 400 {object} string "Bad Request - Invalid input"
+// @Failure 404 {object} string "Not Found - Resource doesn't exist"
+// @Failure 500 {object} string "Internal Server Error"
+// @Router /api/v1/categories/{categoryID} [get]
+func (cfg *APIConfig) GetCategoryHandler(w http.ResponseWriter, r *http.Request) {
+	logger.Log.Info("entered get category handler")
+
+	categoryID, err := uuid.Parse(r.PathValue("categoryID"))
+	if err != nil {
+		http.Error(w, "Invalid category id", http.StatusBadRequest)
+		return
+	}
+
+	category, err := cfg.DB.GetCategoryByID(r.Context(), categoryID)
+	if errors.Is(err, sql.ErrNoRows) {
+		http.Error(w, "category not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		logger.Log.Error("failed to fetch category", zap.String("categoryID", categoryID.String()), zap.Error(err))
+		http.Error(w, "databse operation failed", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(categoryResponse(category)); err != nil {
+		http.Error(w, "failed to encode category", http.StatusInternalServerError)
+		return
+	}
+}
+
+// @Summary Create a category
+// @Description admin can create a category, at the top level or under parent_id. The slug must be unique among its siblings and defaults to one derived from the name
+// @Tags categories
+// @Accept json
+// @Produce json
+// @Param request body models.CategoryRequest true "category"
+// @Success 201 {object} models.CategoryResponse
+// @Failure 400 {object} string "Bad Request - Invalid input"
+// @Failure 401 {object} string "Unauthorized - Missing/invalid credentials"
+// @Failure 403 {object} string "Forbidden - Insufficient permissions"
+// @Failure 409 {object} string "Conflict - slug already used under the parent"
+// @Failure 500 {object} string "Internal Server Error"
+// @Router /api/v1/admin/categories [post]
+// @Security BearerAuth
+func (cfg *APIConfig) CreateCategoryHandler(w http.ResponseWriter, r *http.Request) {
+	logger.Log.Info("entered create category handler")
+
+	req, ok := decodeCategoryRequest(w, r)
+	if !ok {
+		return
+	}
+
+	category, err := cfg.withCategoryTree(r.Context(), func(q *database.Queries) (database.Category, error) {
+		path, err := categoryPath(r.Context(), q, req.ParentID, req.Slug)
+		if err != nil {
+			return database.Category{}, err
+		}
+		return q.CreateCategory(r.Context(), database.CreateCategoryParams{
+			ParentID: nullUUID(req.ParentID),
+			Name:     req.Name,
+			Slug:     req.Slug,
+			Path:     path,
+		})
+	})
+	if err != nil {
+		writeCategoryError(w, err)
+		return
+	}
+	logger.Log.Info("category created", zap.String("categoryID", category.ID.String()), zap.String("path", category.Path))
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	if err := json.NewEncoder(w).Encode(categoryResponse(category)); err != nil {
+		http.Error(w, "failed to encode category", http.StatusInternalServerError)
+		return
+	}
+}
+
+// @Summary Update a category
+// @Description admin can rename a category or move it under another parent. The paths of its descendants follow, and the products in the moved subtree get a new version and ETag since their responses embed the category
+// @Tags categories
+// @Accept json
+// @Produce json
+// @Param categoryID path string true "categoryID"
+// @Param request body models.CategoryRequest true "category"
+// @Success 200 {object} models.CategoryResponse
+// @Failure 400 {object} string "Bad Request - Invalid input"
+// @Failure 401 {object} string "Unauthorized - Missing/invalid credentials"
+// @Failure 403 {object} string "Forbidden - Insufficient permissions"
+// @Failure 404 {object} string "Not Found - Resource doesn't exist"
+// @Failure 409 {object} string "Conflict - slug already used under the parent, or the move would create a cycle"
+// @Failure 500 {object} string "Internal Server Error"
+// @Router /api/v1/admin/categories/{categoryID} [put]
+// @Security BearerAuth
+func (cfg *APIConfig) UpdateCategoryHandler(w http.ResponseWriter, r *http.Request) {
+	logger.Log.Info("entered update category handler")
+
+	categoryID, err := uuid.Parse(r.PathValue("categoryID"))
+	if err != nil {
+		http.Error(w, "Invalid category id", http.StatusBadRequest)
+		return
+	}
+	req, ok := decodeCategoryRequest(w, r)
+	if !ok {
+		return
+	}
+
+	category, err := cfg.withCategoryTree(r.Context(), func(q *database.Queries) (database.Category, error) {
+		current, err := q.GetCategoryByID(r.Context(), categoryID)
+		if errors.Is(err, sql.ErrNoRows) {
+			return database.Category{}, errCategoryNotFound
+		}
+		if err != nil {
+			return database.Category{}, err
+		}
+		path, err := categoryPath(r.Context(), q, req.ParentID, req.Slug)
+		if err != nil {
+			return database.Category{}, err
+		}
+		if strings.HasPrefix(path, current.Path+"/") {
+			return database.Category{}, errCategoryCycle
+		}
+		if req.Name != current.Name || path != current.Path {
+			if err := q.TouchCategoryProducts(r.Context(), current.Path); err != nil {
+				return database.Category{}, err
+			}
+		}
+
+		updated, err := q.UpdateCategory(r.Context(), database.UpdateCategoryParams{
+			ID:       categoryID,
+			ParentID: nullUUID(req.ParentID),
+			Name:     req.Name,
+			Slug:     req.Slug,
+			Path:     path,
+		})
+		if err != nil {
+			return database.Category{}, err
+		}
+		if path != current.Path {
+			err = q.MoveCategoryDescendants(r.Context(), database.MoveCategoryDescendantsParams{
+				OldPath: current.Path,
+				NewPath: path,
+			})
+		}
+		return updated, err
+	})
+	if err != nil {
+		writeCategoryError(w, err)
+		return
+	}
+	logger.Log.Info("category updated", zap.String("categoryID", category.ID.String()), zap.String("path", category.Path))
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(categoryResponse(category)); err != nil {
+		http.Error(w, "failed to encode category", http.StatusInternalServerError)
+		return
+	}
+}
+
+// @Summary Delete a category
+// @Description admin can delete a category that has no children. Products in it are unassigned from it, not deleted, and get a new version and ETag
+// @Tags categories
+// @Param categoryID path string true "categoryID"
+// @Success 204 {string} string "No content"
+// @Failure 400 {object} string "Bad Request - Invalid input"
+// @Failure 401 {object} string "Unauthorized - Missing/invalid credentials"
+// @Failure 403 {object} string "Forbidden - Insufficient permissions"
+// @Failure 404 {object} string "Not Found - Resource doesn't exist"
+// @Failure 409 {object} string "Conflict - category still has children"
+// @Failure 500 {object} string "Internal Server Error"
+// @Router /api/v1/admin/categories/{categoryID} [delete]
+// @Security BearerAuth
+func (cfg *APIConfig) DeleteCategoryHandler(w http.ResponseWriter, r *http.Request) {
+	logger.Log.Info("entered delete category handler")
+
+	categoryID, err := uuid.Parse(r.PathValue("categoryID"))
+	if err != nil {
+		http.Error(w, "Invalid category id", http.StatusBadRequest)
+		return
+	}
+
+	deleted, err := cfg.DB.DeleteCategory(r.Context(), categoryID)
+	if isPQError(err, pqForeignKeyViolation) {
+		http.Error(w, "category still has children", http.StatusConflict)
+		return
+	}
+	if err != nil {
+		logger.Log.Error("failed to delete category", zap.String("categoryID", categoryID.String()), zap.Error(err))
+		http.Error(w, "databse deletion failed", http.StatusInternalServerError)
+		return
+	}
+	if deleted == 0 {
+		http.Error(w, "category not found", http.StatusNotFound)
+		return
+	}
+	logger.Log.Info("category deleted", zap.String("categoryID", categoryID.String()))
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// withCategoryTree runs write in a transaction holding the category tree
+// lock.
+func (cfg *APIConfig) withCategoryTree(ctx context.Context, write func(*database.Queries) (database.Category, error)) (database.Category, error) {
+	tx, err := cfg.DBConn.BeginTx(ctx, nil)
+	if err != nil {
+		return database.Category{}, err
+	}
+	defer tx.Rollback()
+	qtx := cfg.txQueries(tx)
+
+	if err := qtx.LockCategoryTree(ctx, categoryTreeLockKey); err != nil {
+		return database.Category{}, err
+	}
+	category, err := write(qtx)
+	if err != nil {
+		return database.Category{}, err
+	}
+	if err := tx.Commit(); err != nil {
+		return database.Category{}, err
+	}
+	return category, nil
+}
+
+// categoryPath returns the path of a category with the given slug under
+// parentID, or at the top level when parentID is nil.
+func categoryPath(ctx context.Context, q *database.Queries, parentID *uuid.UUID, slug string) (string, error) {
+	if parentID == nil {
+		return slug, nil
+	}
+	parent, err := q.GetCategoryByID(ctx, *parentID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", fmt.Errorf("%w: parent %s", errUnknownCategory, parentID)
+	}
+	if err != nil {
+		return "", err
+	}
+	return parent.Path + "/" + slug, nil
+}
+
+func writeCategoryError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, errCategoryNotFound):
+		http.Error(w, err.Error(), http.StatusNotFound)
+	case errors.Is(err, errUnknownCategory):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	case errors.Is(err, errCategoryCycle):
+		http.Error(w, err.Error(), http.StatusConflict)
+	case isPQError(err, pqUniqueViolation):
+		http.Error(w, "a category with this slug already exists under the parent", http.StatusConflict)
+	default:
+		logger.Log.Error("failed to write category", zap.Error(err))
+		http.Error(w, "databse operation failed", http.StatusInternalServerError)
+	}
+}
+
+func decodeCategoryRequest(w http.ResponseWriter, r *http.Request) (models.CategoryRequest, bool) {
+	var req models.CategoryRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return req, false
+	}
+	req.Name = strings.TrimSpace(req.Name)
+	if req.Name == "" {
+		http.Error(w, "name is required", http.StatusBadRequest)
+		return req, false
+	}
+	if req.Slug == "" {
+		req.Slug = slugify(req.Name)
+	}
+	if !slugPattern.MatchString(req.Slug) {
+		http.Error(w, "slug must be lowercase letters and digits separated by single dashes", http.StatusBadRequest)
+		return req, false
+	}
+	return req, true
+}
+
+// slugify lowercases name and joins its runs of ASCII letters and digits
+// with dashes, "Phones & Tablets" becomes "phones-tablets".
+func slugify(name string) string {
+	words := strings.FieldsFunc(strings.ToLower(name), func(r rune) bool {
+		return (r < 'a' || r > 'z') && (r < '0' || r > '9')
+	})
+	return strings.Join(words, "-")
+}
+
+// setProductCategories replaces the categories of a product. It is meant to
+// run inside the transaction that writes the product.
+func setProductCategories(ctx context.Context, q *database.Queries, productID uuid.UUID, categoryIDs []uuid.UUID) error {
+	if err := q.ClearProductCategories(ctx, productID); err != nil {
+		return err
+	}
+	if len(categoryIDs) == 0 {
+		return nil
+	}
+	err := q.AddProductCategories(ctx, database.AddProductCategoriesParams{
+		ProductID:   productID,
+		CategoryIds: categoryIDs,
+	})
+	if isPQError(err, pqForeignKeyViolation) {
+		return errUnknownCategory
+	}
+	return err
+}
+
+func categoryResponse(category database.Category) models.CategoryResponse {
+	resp := models.CategoryResponse{
+		ID:        category.ID,
+		Name:      category.Name,
+		Slug:      category.Slug,
+		Path:      category.Path,
+		CreatedAt: category.CreatedAt,
+		UpdatedAt: category.UpdatedAt,
+	}
+	if category.ParentID.Valid {
+		resp.ParentID = &category.ParentID.UUID
+	}
+	return resp
+}
+
+func nullUUID(id *uuid.UUID) uuid.NullUUID {
+	if id == nil {
+		return uuid.NullUUID{}
+	}
+	return uuid.NullUUID{UUID: *id, Valid: true}
+}
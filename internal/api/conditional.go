@@ -0,0 +1,91 @@
+package api
+
+import (
+	"database/sql"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/Black-tag/productAPI/internal/database"
+)
+
+// productETag is a strong validator derived from the version column, which
+// is incremented on every write to the row.
+func productETag(product database.Product) string {
+	return fmt.Sprintf(`"%d"`, product.Version)
+}
+
+// setValidators writes the ETag and Last-Modified headers for a product.
+func setValidators(w http.ResponseWriter, product database.Product) {
+	w.Header().Set("ETag", productETag(product))
+	w.Header().Set("Last-Modified", product.UpdatedAt.UTC().Format(http.TimeFormat))
+}
+
+// notModified reports whether a conditional GET can be answered with 304.
+// If-None-Match takes precedence over If-Modified-Since as in RFC 9110.
+func notModified(r *http.Request, etag string, lastModified time.Time) bool {
+	if inm := r.Header.Get("If-None-Match"); inm != "" {
+		return etagListMatches(inm, etag, true)
+	}
+	if ims := r.Header.Get("If-Modified-Since"); ims != "" {
+		t, err := http.ParseTime(ims)
+		if err != nil {
+			return false
+		}
+		return !lastModified.Truncate(time.Second).After(t)
+	}
+	return false
+}
+
+// checkIfMatch evaluates the If-Match precondition of a write against the
+// stored product. It writes 412 or 428 and returns false when the write must
+// not go ahead. The returned version is passed to the update so a write that
+// races with another one after this check still fails.
+func (cfg *APIConfig) checkIfMatch(w http.ResponseWriter, r *http.Request, product database.Product) (sql.NullInt32, bool) {
+	ifMatch := r.Header.Get("If-Match")
+	if ifMatch == "" {
+		if cfg.RequireIfMatch {
+			http.Error(w, "If-Match header required", http.StatusPreconditionRequired)
+			return sql.NullInt32{}, false
+		}
+		return sql.NullInt32{}, true
+	}
+	if !etagListMatches(ifMatch, productETag(product), false) {
+		w.Header().Set("ETag", productETag(product))
+		http.Error(w, "product has been modified", http.StatusPreconditionFailed)
+		return sql.NullInt32{}, false
+	}
+	return sql.NullInt32{Int32: product.Version, Valid: true}, true
+}
+
+// writeLostWrite answers a write that matched no row after checkIfMatch let
+// it through: the product was changed in between, or deleted if no version
+// was being enforced.
+func writeLostWrite(w http.ResponseWriter, expectedVersion sql.NullInt32) {
+	if expectedVersion.Valid {
+		http.Error(w, "product has been modified", http.StatusPreconditionFailed)
+		return
+	}
+	http.Error(w, "product not found", http.StatusNotFound)
+}
+
+// etagListMatches compares etag against a comma separated If-None-Match or
+// If-Match header. If-None-Match uses weak comparison, If-Match strong
+// comparison where weak tags never match.
+func etagListMatches(header, etag string, weak bool) bool {
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" {
+			return true
+		}
+		if weak {
+			if strings.TrimPrefix(candidate, "W/") == strings.TrimPrefix(etag, "W/") {
+				return true
+			}
+		} else if !strings.HasPrefix(candidate, "W/") && candidate == etag {
+			return true
+		}
+	}
+	return false
+}
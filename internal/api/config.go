@@ -1,10 +1,39 @@
 package api
 
 import (
+	"database/sql"
+	"sync/atomic"
+	"time"
+
 	"github.com/Black-tag/productAPI/internal/database"
+	"github.com/Black-tag/productAPI/internal/storage"
+	"github.com/Black-tag/productAPI/internal/tracing"
 )
 
 type APIConfig struct {
 	DB     *database.Queries
+	DBConn *sql.DB
 	SECRET string
+	// AccessTokenTTL and RefreshTokenTTL are the lifetimes of issued tokens.
+	AccessTokenTTL  time.Duration
+	RefreshTokenTTL time.Duration
+	// BcryptCost is the cost new password hashes are made with.
+	BcryptCost int
+	// RequireIfMatch rejects product writes without an If-Match header with
+	// 428 instead of falling back to last-write-wins.
+	RequireIfMatch bool
+	// TrashRetention is how long deleted products stay restorable before
+	// the background purge removes them for good.
+	TrashRetention time.Duration
+	// Storage keeps the files of product images.
+	Storage storage.Storage
+
+	// draining is set once graceful shutdown starts.
+	draining atomic.Bool
+}
+
+// txQueries runs queries in tx. It replaces cfg.DB.WithTx, which would drop
+// the tracing wrapper DB was built with.
+func (cfg *APIConfig) txQueries(tx *sql.Tx) *database.Queries {
+	return database.New(tracing.WrapDB(tx))
 }
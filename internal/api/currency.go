@@ -0,0 +1,272 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"strings"
+
+	"github.com/Black-tag/productAPI/internal/database"
+	"github.com/Black-tag/productAPI/internal/logger"
+	"github.com/Black-tag/productAPI/internal/models"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// exchangeRateScale is the number of fractional digits exchange_rates.rate
+// NUMERIC(20,10) keeps.
+const exchangeRateScale = 10
+
+var errNoExchangeRate = errors.New("no exchange rate")
+
+// priceConverter converts product prices into the currency requested with
+// ?currency=. Rates are loaded once per request. A rate stored for the
+// opposite direction is used inverted when there is no direct one.
+type priceConverter struct {
+	target string
+	rates  map[[2]string]*big.Rat
+}
+
+// newPriceConverter returns nil when the request did not ask for a currency.
+func (cfg *APIConfig) newPriceConverter(r *http.Request) (*priceConverter, error) {
+	target := strings.ToUpper(r.URL.Query().Get("currency"))
+	if target == "" {
+		return nil, nil
+	}
+	if err := (models.Money{Currency: target}).Validate(); err != nil {
+		return nil, err
+	}
+
+	rows, err := cfg.DB.ListExchangeRates(r.Context())
+	if err != nil {
+		return nil, err
+	}
+	c := &priceConverter{target: target, rates: make(map[[2]string]*big.Rat, len(rows))}
+	for _, row := range rows {
+		rate, ok := new(big.Rat).SetString(row.Rate)
+		if !ok {
+			return nil, fmt.Errorf("invalid stored exchange rate %s/%s: %q", row.BaseCurrency, row.QuoteCurrency, row.Rate)
+		}
+		c.rates[[2]string{row.BaseCurrency, row.QuoteCurrency}] = rate
+	}
+	return c, nil
+}
+
+func (c *priceConverter) rate(from string) (*big.Rat, error) {
+	if from == c.target {
+		return big.NewRat(1, 1), nil
+	}
+	if rate, ok := c.rates[[2]string{from, c.target}]; ok {
+		return rate, nil
+	}
+	if rate, ok := c.rates[[2]string{c.target, from}]; ok {
+		return new(big.Rat).Inv(rate), nil
+	}
+	return nil, fmt.Errorf("%w from %s to %s", errNoExchangeRate, from, c.target)
+}
+
+// apply sets the converted price on a product response. It is a no-op on a
+// nil converter.
+func (c *priceConverter) apply(resp *models.ProductResponse) error {
+	if c == nil {
+		return nil
+	}
+	rate, err := c.rate(resp.Price.Currency)
+	if err != nil {
+		return err
+	}
+	converted, err := resp.Price.Convert(c.target, rate)
+	if err != nil {
+		return fmt.Errorf("%w: %s to %s", err, resp.Price, c.target)
+	}
+	resp.ConvertedPrice = &converted
+	resp.ExchangeRate = rate.FloatString(exchangeRateScale)
+	return nil
+}
+
+// writeConversionError answers a failed conversion: a missing rate or a
+// result too large to represent is the client's problem, anything else is
+// ours.
+func writeConversionError(w http.ResponseWriter, err error) {
+	if errors.Is(err, errNoExchangeRate) || errors.Is(err, models.ErrInvalidCurrency) || errors.Is(err, models.ErrConvertOverflow) {
+		http.Error(w, err.Error(), http.StatusUnprocessableEntity)
+		return
+	}
+	logger.Log.Error("failed to convert prices", zap.Error(err))
+	http.Error(w, "databse operation failed", http.StatusInternalServerError)
+}
+
+// @Summary List exchange rates
+// @Description anyone can list the exchange rates used to convert product prices. A rate is the number of units of quote per unit of base
+// @Tags currencies
+// @Produce json
+// @Success 200 {array} models.ExchangeRateResponse
+// @Failure 500 {object} string "Internal Server Error"
+// @Router /api/v1/exchange-rates [get]
+func (cfg *APIConfig) ListExchangeRatesHandler(w http.ResponseWriter, r *http.Request) {
+	logger.Log.Info("entered list exchange rates handler")
+
+	rows, err := cfg.DB.ListExchangeRates(r.Context())
+	if err != nil {
+		logger.Log.Error("failed to list exchange rates", zap.Error(err))
+		http.Error(w, "databse operation failed", http.StatusInternalServerError)
+		return
+	}
+
+	respPayload := make([]models.ExchangeRateResponse, 0, len(rows))
+	for _, row := range rows {
+		respPayload = append(respPayload, exchangeRateResponse(row))
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(respPayload); err != nil {
+		http.Error(w, "failed to encode exchange rates", http.StatusInternalServerError)
+		return
+	}
+}
+
+// @Summary Set an exchange rate
+// @Description admin can create or replace the rate from base to quote currency. The rate is a positive decimal with at most 10 integer and 10 fractional digits
+// @Tags currencies
+// @Accept json
+// @Produce json
+// @Param base path string true "base currency code" example:"USD"
+// @Param quote path string true "quote currency code" example:"EUR"
+// @Param request body models.ExchangeRateRequest true "units of quote per unit of base"
+// @Success 200 {object} models.ExchangeRateResponse
+// @Failure 400 {object} string "Bad Request - Invalid input"
+// @Failure 401 {object} string "Unauthorized - Missing/invalid credentials"
+// @Failure 403 {object} string "Forbidden - Insufficient permissions"
+// @Failure 500 {object} string "Internal Server Error"
+// @Router /api/v1/admin/exchange-rates/{base}/{quote} [put]
+// @Security BearerAuth
+func (cfg *APIConfig) PutExchangeRateHandler(w http.ResponseWriter, r *http.Request) {
+	logger.Log.Info("entered put exchange rate handler")
+
+	userID, ok := r.Context().Value("userID").(uuid.UUID)
+	if !ok {
+		http.Error(w, "userID not in context", http.StatusUnauthorized)
+		return
+	}
+	base, quote, err := currencyPair(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	var req models.ExchangeRateRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+	if err := validateExchangeRate(req.Rate); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	rate, err := cfg.DB.UpsertExchangeRate(r.Context(), database.UpsertExchangeRateParams{
+		BaseCurrency:  base,
+		QuoteCurrency: quote,
+		Rate:          req.Rate,
+		UpdatedBy:     uuid.NullUUID{UUID: userID, Valid: true},
+	})
+	if err != nil {
+		logger.Log.Error("failed to store exchange rate", zap.String("base", base), zap.String("quote", quote), zap.Error(err))
+		http.Error(w, "databse operation failed", http.StatusInternalServerError)
+		return
+	}
+	logger.Log.Info("exchange rate updated",
+		zap.String("base", base),
+		zap.String("quote", quote),
+		zap.String("rate", rate.Rate),
+		zap.String("userID", userID.String()),
+	)
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(exchangeRateResponse(rate)); err != nil {
+		http.Error(w, "failed to encode exchange rate", http.StatusInternalServerError)
+		return
+	}
+}
+
+// @Summary Delete an exchange rate
+// @Description admin can remove the rate from base to quote currency
+// @Tags currencies
+// @Param base path string true "base currency code" example:"USD"
+// @Param quote path string true "quote currency code" example:"EUR"
+// @Success 204 {string} string "No content"
+// @Failure 400 {object} string "Bad Request - Invalid input"
+// @Failure 401 {object} string "Unauthorized - Missing/invalid credentials"
+// @Failure 403 {object} string "Forbidden - Insufficient permissions"
+// @Failure 404 {object} string "Not Found - Resource doesn't exist"
+// @Failure 500 {object} string "Internal Server Error"
+// @Router /api/v1/admin/exchange-rates/{base}/{quote} [delete]
+// @Security BearerAuth
+func (cfg *APIConfig) DeleteExchangeRateHandler(w http.ResponseWriter, r *http.Request) {
+	logger.Log.Info("entered delete exchange rate handler")
+
+	base, quote, err := currencyPair(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	deleted, err := cfg.DB.DeleteExchangeRate(r.Context(), database.DeleteExchangeRateParams{
+		BaseCurrency:  base,
+		QuoteCurrency: quote,
+	})
+	if err != nil {
+		logger.Log.Error("failed to delete exchange rate", zap.String("base", base), zap.String("quote", quote), zap.Error(err))
+		http.Error(w, "databse deletion failed", http.StatusInternalServerError)
+		return
+	}
+	if deleted == 0 {
+		http.Error(w, "exchange rate not found", http.StatusNotFound)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func currencyPair(r *http.Request) (string, string, error) {
+	base := strings.ToUpper(r.PathValue("base"))
+	quote := strings.ToUpper(r.PathValue("quote"))
+	for _, code := range []string{base, quote} {
+		if err := (models.Money{Currency: code}).Validate(); err != nil {
+			return "", "", err
+		}
+	}
+	if base == quote {
+		return "", "", errors.New("base and quote currency must differ")
+	}
+	return base, quote, nil
+}
+
+func validateExchangeRate(rate string) error {
+	whole, frac, _ := strings.Cut(rate, ".")
+	value, ok := new(big.Rat).SetString(rate)
+	if !ok || whole == "" || strings.ContainsAny(rate, "/eE+-") {
+		return errors.New("rate must be a decimal number")
+	}
+	if value.Sign() <= 0 {
+		return errors.New("rate must be positive")
+	}
+	if len(strings.TrimLeft(whole, "0")) > 10 || len(frac) > exchangeRateScale {
+		return errors.New("rate must have at most 10 integer and 10 fractional digits")
+	}
+	return nil
+}
+
+func exchangeRateResponse(rate database.ExchangeRate) models.ExchangeRateResponse {
+	resp := models.ExchangeRateResponse{
+		Base:      rate.BaseCurrency,
+		Quote:     rate.QuoteCurrency,
+		Rate:      rate.Rate,
+		UpdatedAt: rate.UpdatedAt,
+	}
+	if rate.UpdatedBy.Valid {
+		resp.UpdatedBy = &rate.UpdatedBy.UUID
+	}
+	return resp
+}
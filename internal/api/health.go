@@ -0,0 +1,139 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Black-tag/productAPI/internal/db"
+	"github.com/Black-tag/productAPI/internal/logger"
+	"github.com/Black-tag/productAPI/internal/models"
+	"go.uber.org/zap"
+)
+
+const readinessCheckTimeout = 2 * time.Second
+
+// appliedMigrationsQuery reads the versions goose considers applied: those
+// whose latest row records them as applied. It is plain SQL since
+// goose_db_version is created by goose, not by the migrations sqlc reads.
+const appliedMigrationsQuery = `
+SELECT version_id
+FROM goose_db_version g
+WHERE is_applied
+  AND id = (SELECT MAX(id) FROM goose_db_version WHERE version_id = g.version_id)`
+
+// @Summary Liveness probe
+// @Description reports that the process is up. It checks no dependencies, so a failing database does not get the process restarted
+// @Tags health
+// @Produce json
+// @Success 200 {object} models.HealthResponse
+// @Router /healthz [get]
+func (cfg *APIConfig) HealthzHandler(w http.ResponseWriter, r *http.Request) {
+	writeHealth(w, http.StatusOK, models.HealthResponse{Status: "ok"})
+}
+
+// @Summary Readiness probe
+// @Description reports whether the service can take traffic: the database answers and every migration this build ships with is applied. Each check reports its status and latency. During graceful shutdown it answers 503 without running the checks
+// @Tags health
+// @Produce json
+// @Success 200 {object} models.HealthResponse
+// @Failure 503 {object} models.HealthResponse
+// @Router /readyz [get]
+func (cfg *APIConfig) ReadyzHandler(w http.ResponseWriter, r *http.Request) {
+	if cfg.draining.Load() {
+		writeHealth(w, http.StatusServiceUnavailable, models.HealthResponse{Status: "shutting_down"})
+		return
+	}
+
+	checks := map[string]func(context.Context) error{
+		"database":   cfg.DBConn.PingContext,
+		"migrations": cfg.checkMigrations,
+	}
+	resp := models.HealthResponse{Status: "ok", Checks: make(map[string]models.HealthCheck, len(checks))}
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for name, check := range checks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			ctx, cancel := context.WithTimeout(r.Context(), readinessCheckTimeout)
+			defer cancel()
+			start := time.Now()
+			err := check(ctx)
+			result := models.HealthCheck{
+				Status:    "ok",
+				LatencyMS: float64(time.Since(start).Microseconds()) / 1000,
+			}
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				logger.Log.Warn("readiness check failed", zap.String("check", name), zap.Error(err))
+				result.Status = "fail"
+				result.Error = err.Error()
+				resp.Status = "unavailable"
+			}
+			resp.Checks[name] = result
+		}()
+	}
+	wg.Wait()
+
+	status := http.StatusOK
+	if resp.Status != "ok" {
+		status = http.StatusServiceUnavailable
+	}
+	writeHealth(w, status, resp)
+}
+
+// StartDraining makes /readyz report not ready, so load balancers stop
+// sending traffic while in-flight requests finish.
+func (cfg *APIConfig) StartDraining() {
+	cfg.draining.Store(true)
+}
+
+// checkMigrations fails while any migration this build ships with is not
+// applied, including one skipped because it sorts before the newest applied
+// version. Applied migrations the build does not know are fine, during a
+// rolling deploy the old instances keep serving after the new ones migrated.
+func (cfg *APIConfig) checkMigrations(ctx context.Context) error {
+	rows, err := cfg.DBConn.QueryContext(ctx, appliedMigrationsQuery)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	applied := map[int64]bool{}
+	for rows.Next() {
+		var version int64
+		if err := rows.Scan(&version); err != nil {
+			return err
+		}
+		applied[version] = true
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	var missing []string
+	for _, version := range db.Migrations() {
+		if !applied[version] {
+			missing = append(missing, strconv.FormatInt(version, 10))
+		}
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("database is missing migrations %s", strings.Join(missing, ", "))
+	}
+	return nil
+}
+
+func writeHealth(w http.ResponseWriter, status int, resp models.HealthResponse) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		logger.Log.Error("failed to encode health response", zap.Error(err))
+	}
+}
@@ -0,0 +1,332 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/Black-tag/productAPI/internal/database"
+	"github.com/Black-tag/productAPI/internal/imaging"
+	"github.com/Black-tag/productAPI/internal/logger"
+	"github.com/Black-tag/productAPI/internal/models"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+const (
+	maxImageSize     = 10 << 20
+	maxProductImages = 20
+	thumbnailSize    = 320
+)
+
+var (
+	errImageNotFound = errors.New("image not found")
+	errTooManyImages = fmt.Errorf("a product can have at most %d images", maxProductImages)
+	errInvalidOrder  = errors.New("image_ids must list every image of the product once")
+	errImageTooLarge = fmt.Errorf("image must not be larger than %d MiB", maxImageSize>>20)
+	errMissingImage  = errors.New(`expected a multipart/form-data body with an "image" file`)
+)
+
+// @Summary Upload a product image
+// @Description the owner of a product, or roles with product:update:any, can upload a JPEG, PNG or GIF image of up to 10 MiB as the "image" field of a multipart form. The format is detected from the file content. A thumbnail is generated and the image is appended to the images of the product
+// @Tags products
+// @Accept multipart/form-data
+// @Produce json
+// @Param productID path string true "productID"
+// @Param image formData file true "image file"
+// @Success 201 {object} models.ProductImage
+// @Failure 400 {object} string "Bad Request - Invalid input"
+// @Failure 401 {object} string "Unauthorized - Missing/invalid credentials"
+// @Failure 403 {object} string "Forbidden - Insufficient permissions"
+// @Failure 404 {object} string "Not Found - Resource doesn't exist"
+// @Failure 409 {object} string "Conflict - product already has the maximum number of images"
+// @Failure 413 {object} string "Request Entity Too Large - image too large"
+// @Failure 415 {object} string "Unsupported Media Type - not a JPEG, PNG or GIF image"
+// @Failure 500 {object} string "Internal Server Error"
+// @Router /api/v1/product/{productID}/images [post]
+// @Security BearerAuth
+func (cfg *APIConfig) UploadProductImageHandler(w http.ResponseWriter, r *http.Request) {
+	logger.Log.Info("entered upload product image handler")
+
+	productID, ok := cfg.authorizeProductEdit(w, r)
+	if !ok {
+		return
+	}
+	data, err := readImageUpload(w, r)
+	if err != nil {
+		writeImageError(w, err)
+		return
+	}
+	img, err := imaging.Decode(data)
+	if err != nil {
+		writeImageError(w, err)
+		return
+	}
+	thumb, thumbType, thumbExt, err := img.Thumbnail(thumbnailSize)
+	if err != nil {
+		logger.Log.Error("failed to generate thumbnail", zap.String("productID", productID.String()), zap.Error(err))
+		http.Error(w, "failed to process image", http.StatusInternalServerError)
+		return
+	}
+
+	// files are stored first and removed again if the image cannot be
+	// recorded, so a recorded image always has its files
+	imageID := uuid.New()
+	key := fmt.Sprintf("products/%s/%s%s", productID, imageID, img.Extension())
+	thumbKey := fmt.Sprintf("products/%s/%s_thumb%s", productID, imageID, thumbExt)
+	if err := cfg.Storage.Put(r.Context(), key, bytes.NewReader(data), img.ContentType); err != nil {
+		logger.Log.Error("failed to store image", zap.String("key", key), zap.Error(err))
+		http.Error(w, "failed to store image", http.StatusInternalServerError)
+		return
+	}
+	if err := cfg.Storage.Put(r.Context(), thumbKey, bytes.NewReader(thumb), thumbType); err != nil {
+		logger.Log.Error("failed to store thumbnail", zap.String("key", thumbKey), zap.Error(err))
+		cfg.deleteImageFiles(r.Context(), key)
+		http.Error(w, "failed to store image", http.StatusInternalServerError)
+		return
+	}
+
+	image, _, err := withTouchedProduct(r.Context(), cfg, productID, func(q *database.Queries) (database.ProductImage, error) {
+		count, err := q.CountProductImages(r.Context(), productID)
+		if err != nil {
+			return database.ProductImage{}, err
+		}
+		if count >= maxProductImages {
+			return database.ProductImage{}, errTooManyImages
+		}
+		return q.CreateProductImage(r.Context(), database.CreateProductImageParams{
+			ID:           imageID,
+			ProductID:    productID,
+			StorageKey:   key,
+			ThumbnailKey: thumbKey,
+			ContentType:  img.ContentType,
+			SizeBytes:    int64(len(data)),
+			Width:        int32(img.Width),
+			Height:       int32(img.Height),
+		})
+	})
+	if err != nil {
+		cfg.deleteImageFiles(context.WithoutCancel(r.Context()), key, thumbKey)
+		writeImageError(w, err)
+		return
+	}
+	logger.Log.Info("product image uploaded",
+		zap.String("productID", productID.String()),
+		zap.String("imageID", image.ID.String()),
+		zap.Int64("size", image.SizeBytes),
+	)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	if err := json.NewEncoder(w).Encode(cfg.productImage(image)); err != nil {
+		http.Error(w, "failed to encode image", http.StatusInternalServerError)
+		return
+	}
+}
+
+// @Summary Delete a product image
+// @Description the owner of a product, or roles with product:update:any, can delete one of its images. Its files are removed from storage
+// @Tags products
+// @Param productID path string true "productID"
+// @Param imageID path string true "imageID"
+// @Success 204 {string} string "No content"
+// @Failure 400 {object} string "Bad Request - Invalid input"
+// @Failure 401 {object} string "Unauthorized - Missing/invalid credentials"
+// @Failure 403 {object} string "Forbidden - Insufficient permissions"
+// @Failure 404 {object} string "Not Found - Resource doesn't exist"
+// @Failure 500 {object} string "Internal Server Error"
+// @Router /api/v1/product/{productID}/images/{imageID} [delete]
+// @Security BearerAuth
+func (cfg *APIConfig) DeleteProductImageHandler(w http.ResponseWriter, r *http.Request) {
+	logger.Log.Info("entered delete product image handler")
+
+	imageID, err := uuid.Parse(r.PathValue("imageID"))
+	if err != nil {
+		http.Error(w, "Invalid image id", http.StatusBadRequest)
+		return
+	}
+	productID, ok := cfg.authorizeProductEdit(w, r)
+	if !ok {
+		return
+	}
+
+	image, _, err := withTouchedProduct(r.Context(), cfg, productID, func(q *database.Queries) (database.ProductImage, error) {
+		image, err := q.DeleteProductImage(r.Context(), database.DeleteProductImageParams{
+			ID:        imageID,
+			ProductID: productID,
+		})
+		if errors.Is(err, sql.ErrNoRows) {
+			return database.ProductImage{}, errImageNotFound
+		}
+		return image, err
+	})
+	if err != nil {
+		writeImageError(w, err)
+		return
+	}
+	cfg.deleteImageFiles(r.Context(), image.StorageKey, image.ThumbnailKey)
+	logger.Log.Info("product image deleted", zap.String("productID", productID.String()), zap.String("imageID", imageID.String()))
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// @Summary Reorder product images
+// @Description the owner of a product, or roles with product:update:any, can change the order its images are listed in by sending the ids of all its images in the new order
+// @Tags products
+// @Accept json
+// @Produce json
+// @Param productID path string true "productID"
+// @Param request body models.ImageOrderRequest true "image order"
+// @Success 200 {array} models.ProductImage
+// @Failure 400 {object} string "Bad Request - Invalid input"
+// @Failure 401 {object} string "Unauthorized - Missing/invalid credentials"
+// @Failure 403 {object} string "Forbidden - Insufficient permissions"
+// @Failure 404 {object} string "Not Found - Resource doesn't exist"
+// @Failure 500 {object} string "Internal Server Error"
+// @Router /api/v1/product/{productID}/images/order [put]
+// @Security BearerAuth
+func (cfg *APIConfig) ReorderProductImagesHandler(w http.ResponseWriter, r *http.Request) {
+	logger.Log.Info("entered reorder product images handler")
+
+	productID, ok := cfg.authorizeProductEdit(w, r)
+	if !ok {
+		return
+	}
+	var req models.ImageOrderRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+	seen := make(map[uuid.UUID]bool, len(req.ImageIDs))
+	for _, id := range req.ImageIDs {
+		if seen[id] {
+			http.Error(w, errInvalidOrder.Error(), http.StatusBadRequest)
+			return
+		}
+		seen[id] = true
+	}
+
+	images, _, err := withTouchedProduct(r.Context(), cfg, productID, func(q *database.Queries) ([]database.ProductImage, error) {
+		count, err := q.CountProductImages(r.Context(), productID)
+		if err != nil {
+			return nil, err
+		}
+		if int64(len(req.ImageIDs)) != count {
+			return nil, errInvalidOrder
+		}
+		moved, err := q.ReorderProductImages(r.Context(), database.ReorderProductImagesParams{
+			ProductID: productID,
+			ImageIds:  req.ImageIDs,
+		})
+		if err != nil {
+			return nil, err
+		}
+		if moved != count {
+			return nil, errInvalidOrder
+		}
+		return q.ListImagesForProducts(r.Context(), []uuid.UUID{productID})
+	})
+	if err != nil {
+		writeImageError(w, err)
+		return
+	}
+	logger.Log.Info("product images reordered", zap.String("productID", productID.String()))
+
+	respPayload := make([]models.ProductImage, 0, len(images))
+	for _, image := range images {
+		respPayload = append(respPayload, cfg.productImage(image))
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(respPayload); err != nil {
+		http.Error(w, "failed to encode images", http.StatusInternalServerError)
+		return
+	}
+}
+
+// readImageUpload reads the "image" file of a multipart upload without
+// buffering more than maxImageSize of it.
+func readImageUpload(w http.ResponseWriter, r *http.Request) ([]byte, error) {
+	// leaves room for the multipart headers around the file
+	r.Body = http.MaxBytesReader(w, r.Body, maxImageSize+64<<10)
+	mr, err := r.MultipartReader()
+	if err != nil {
+		return nil, errMissingImage
+	}
+	for {
+		part, err := mr.NextPart()
+		if errors.Is(err, io.EOF) {
+			return nil, errMissingImage
+		}
+		if err != nil {
+			return nil, multipartError(err)
+		}
+		if part.FormName() != "image" {
+			continue
+		}
+		data, err := io.ReadAll(io.LimitReader(part, maxImageSize+1))
+		if err != nil {
+			return nil, multipartError(err)
+		}
+		if len(data) > maxImageSize {
+			return nil, errImageTooLarge
+		}
+		return data, nil
+	}
+}
+
+func multipartError(err error) error {
+	var maxBytesErr *http.MaxBytesError
+	if errors.As(err, &maxBytesErr) {
+		return errImageTooLarge
+	}
+	return errMissingImage
+}
+
+func writeImageError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, errProductNotFound), errors.Is(err, errImageNotFound):
+		http.Error(w, err.Error(), http.StatusNotFound)
+	case errors.Is(err, errImageTooLarge), errors.Is(err, imaging.ErrTooManyPixels):
+		http.Error(w, err.Error(), http.StatusRequestEntityTooLarge)
+	case errors.Is(err, imaging.ErrUnsupportedFormat):
+		http.Error(w, err.Error(), http.StatusUnsupportedMediaType)
+	case errors.Is(err, errMissingImage), errors.Is(err, errInvalidOrder), errors.Is(err, imaging.ErrCorrupt):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	case errors.Is(err, errTooManyImages):
+		http.Error(w, err.Error(), http.StatusConflict)
+	default:
+		logger.Log.Error("failed to write product image", zap.Error(err))
+		http.Error(w, "databse operation failed", http.StatusInternalServerError)
+	}
+}
+
+// deleteImageFiles removes image files from storage. Failures are only
+// logged, a leftover file is harmless and the database no longer refers to
+// it. Empty keys are skipped.
+func (cfg *APIConfig) deleteImageFiles(ctx context.Context, keys ...string) {
+	for _, key := range keys {
+		if key == "" {
+			continue
+		}
+		if err := cfg.Storage.Delete(ctx, key); err != nil {
+			logger.Log.Error("failed to delete image file", zap.String("key", key), zap.Error(err))
+		}
+	}
+}
+
+func (cfg *APIConfig) productImage(image database.ProductImage) models.ProductImage {
+	return models.ProductImage{
+		ID:           image.ID,
+		URL:          cfg.Storage.URL(image.StorageKey),
+		ThumbnailURL: cfg.Storage.URL(image.ThumbnailKey),
+		ContentType:  image.ContentType,
+		Width:        image.Width,
+		Height:       image.Height,
+		SizeBytes:    image.SizeBytes,
+		CreatedAt:    image.CreatedAt,
+	}
+}
@@ -0,0 +1,564 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"slices"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/Black-tag/productAPI/internal/database"
+	"github.com/Black-tag/productAPI/internal/logger"
+	"github.com/Black-tag/productAPI/internal/models"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+const maxMovementReasonLength = 200
+
+var (
+	errInsufficientStock = errors.New("insufficient stock")
+	errStockOverflow     = errors.New("stock level out of range")
+	errUnknownWarehouse  = errors.New("unknown warehouse")
+	errUnknownVariant    = errors.New("unknown variant")
+)
+
+// @Summary Record a stock movement
+// @Description roles with inventory:manage can receive, sell, adjust or return stock of a product, or of one of its variants, in a warehouse, the default warehouse unless warehouse_id is given. Movements that would take the on-hand quantity of the warehouse below zero are rejected. An adjust needs a reason. The product gets a new version and ETag, since its response carries the on-hand total
+// @Tags inventory
+// @Accept json
+// @Produce json
+// @Param productID path string true "productID"
+// @Param request body models.StockMovementRequest true "movement"
+// @Success 201 {object} models.StockMovementResponse
+// @Failure 400 {object} string "Bad Request - Invalid input"
+// @Failure 401 {object} string "Unauthorized - Missing/invalid credentials"
+// @Failure 403 {object} string "Forbidden - Insufficient permissions"
+// @Failure 404 {object} string "Not Found - Resource doesn't exist"
+// @Failure 409 {object} string "Conflict - not enough stock on hand"
+// @Failure 500 {object} string "Internal Server Error"
+// @Router /api/v1/product/{productID}/stock/movements [post]
+// @Security BearerAuth
+func (cfg *APIConfig) CreateStockMovementHandler(w http.ResponseWriter, r *http.Request) {
+	logger.Log.Info("entered create stock movement handler")
+
+	userID, ok := r.Context().Value("userID").(uuid.UUID)
+	if !ok {
+		http.Error(w, "userID not in context", http.StatusUnauthorized)
+		return
+	}
+	productID, err := uuid.Parse(r.PathValue("productID"))
+	if err != nil {
+		http.Error(w, "Invalid product id", http.StatusBadRequest)
+		return
+	}
+	var req models.StockMovementRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+	delta, err := movementDelta(&req)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if _, err := cfg.DB.GetProductByID(r.Context(), productID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			http.Error(w, "product not found", http.StatusNotFound)
+			return
+		}
+		logger.Log.Error("Failed to fetch product by ID", zap.String("productID", productID.String()), zap.Error(err))
+		http.Error(w, "databse operation failed", http.StatusInternalServerError)
+		return
+	}
+
+	movement, err := cfg.recordStockMovement(r.Context(), req.WarehouseID, req.VariantID, database.CreateStockMovementParams{
+		ProductID: productID,
+		Kind:      req.Kind,
+		Quantity:  delta,
+		Reason:    req.Reason,
+		ActorID:   uuid.NullUUID{UUID: userID, Valid: true},
+	})
+	if err != nil {
+		writeStockError(w, err)
+		return
+	}
+	logger.Log.Info("stock movement recorded",
+		zap.String("productID", productID.String()),
+		zap.String("warehouseID", movement.WarehouseID.String()),
+		zap.String("kind", movement.Kind),
+		zap.Int32("quantity", movement.Quantity),
+		zap.Int32("onHand", movement.BalanceAfter),
+		zap.String("userID", userID.String()),
+	)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	if err := json.NewEncoder(w).Encode(stockMovementResponse(movement)); err != nil {
+		http.Error(w, "failed to encode stock movement", http.StatusInternalServerError)
+		return
+	}
+}
+
+// @Summary Get the stock of a product
+// @Description anyone can read the on-hand quantity of a product in total and per warehouse and variant. Warehouses the product never had a stock movement in are left out
+// @Tags inventory
+// @Produce json
+// @Param productID path string true "productID"
+// @Success 200 {object} models.StockLevelResponse
+// @Failure 400 {object} string "Bad Request - Invalid input"
+// @Failure 404 {object} string "Not Found - Resource doesn't exist"
+// @Failure 500 {object} string "Internal Server Error"
+// @Router /api/v1/product/{productID}/stock [get]
+func (cfg *APIConfig) GetStockLevelHandler(w http.ResponseWriter, r *http.Request) {
+	logger.Log.Info("entered get stock level handler")
+
+	productID, err := uuid.Parse(r.PathValue("productID"))
+	if err != nil {
+		http.Error(w, "Invalid product id", http.StatusBadRequest)
+		return
+	}
+	if _, err := cfg.DB.GetProductByID(r.Context(), productID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			http.Error(w, "product not found", http.StatusNotFound)
+			return
+		}
+		logger.Log.Error("Failed to fetch product by ID", zap.String("productID", productID.String()), zap.Error(err))
+		http.Error(w, "databse operation failed", http.StatusInternalServerError)
+		return
+	}
+
+	levels, err := cfg.DB.ListProductStockLevels(r.Context(), productID)
+	if err != nil {
+		logger.Log.Error("failed to fetch stock levels", zap.String("productID", productID.String()), zap.Error(err))
+		http.Error(w, "databse operation failed", http.StatusInternalServerError)
+		return
+	}
+
+	respPayload := models.StockLevelResponse{
+		ProductID:  productID,
+		Warehouses: make([]models.WarehouseStock, 0, len(levels)),
+	}
+	for _, level := range levels {
+		respPayload.OnHand += int64(level.OnHand)
+		stock := models.WarehouseStock{
+			WarehouseID: level.WarehouseID,
+			Code:        level.Code,
+			Name:        level.Name,
+			VariantSKU:  level.VariantSku.String,
+			OnHand:      level.OnHand,
+			UpdatedAt:   level.UpdatedAt,
+		}
+		if level.VariantID.Valid {
+			stock.VariantID = &level.VariantID.UUID
+		}
+		respPayload.Warehouses = append(respPayload.Warehouses, stock)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(respPayload); err != nil {
+		http.Error(w, "failed to encode stock level", http.StatusInternalServerError)
+		return
+	}
+}
+
+// @Summary List the stock movements of a product
+// @Description roles with inventory:manage can read the stock ledger of a product, newest first
+// @Tags inventory
+// @Produce json
+// @Param productID path string true "productID"
+// @Param warehouse query string false "only movements in this warehouse id"
+// @Param variant query string false "only movements of this variant id"
+// @Param limit query int false "page size (default 20, max 100)"
+// @Param offset query int false "number of movements to skip"
+// @Success 200 {object} models.StockMovementListResponse
+// @Failure 400 {object} string "Bad Request - Invalid input"
+// @Failure 401 {object} string "Unauthorized - Missing/invalid credentials"
+// @Failure 403 {object} string "Forbidden - Insufficient permissions"
+// @Failure 404 {object} string "Not Found - Resource doesn't exist"
+// @Failure 500 {object} string "Internal Server Error"
+// @Router /api/v1/product/{productID}/stock/movements [get]
+// @Security BearerAuth
+func (cfg *APIConfig) ListStockMovementsHandler(w http.ResponseWriter, r *http.Request) {
+	logger.Log.Info("entered list stock movements handler")
+
+	productID, err := uuid.Parse(r.PathValue("productID"))
+	if err != nil {
+		http.Error(w, "Invalid product id", http.StatusBadRequest)
+		return
+	}
+	limit, offset, err := parsePagination(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	params := database.ListStockMovementsParams{
+		ProductID:  productID,
+		PageSize:   limit,
+		PageOffset: offset,
+	}
+	if v := r.URL.Query().Get("warehouse"); v != "" {
+		warehouseID, err := uuid.Parse(v)
+		if err != nil {
+			http.Error(w, "Invalid warehouse id", http.StatusBadRequest)
+			return
+		}
+		params.WarehouseID = uuid.NullUUID{UUID: warehouseID, Valid: true}
+	}
+	if v := r.URL.Query().Get("variant"); v != "" {
+		variantID, err := uuid.Parse(v)
+		if err != nil {
+			http.Error(w, "Invalid variant id", http.StatusBadRequest)
+			return
+		}
+		params.VariantID = uuid.NullUUID{UUID: variantID, Valid: true}
+	}
+	if _, err := cfg.DB.GetProductByID(r.Context(), productID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			http.Error(w, "product not found", http.StatusNotFound)
+			return
+		}
+		logger.Log.Error("Failed to fetch product by ID", zap.String("productID", productID.String()), zap.Error(err))
+		http.Error(w, "databse operation failed", http.StatusInternalServerError)
+		return
+	}
+
+	rows, err := cfg.DB.ListStockMovements(r.Context(), params)
+	if err != nil {
+		logger.Log.Error("failed to list stock movements", zap.String("productID", productID.String()), zap.Error(err))
+		http.Error(w, "databse operation failed", http.StatusInternalServerError)
+		return
+	}
+
+	respPayload := models.StockMovementListResponse{
+		Movements: make([]models.StockMovementResponse, 0, len(rows)),
+		Limit:     limit,
+		Offset:    offset,
+	}
+	for _, row := range rows {
+		respPayload.Movements = append(respPayload.Movements, stockMovementResponse(row))
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(respPayload); err != nil {
+		http.Error(w, "failed to encode stock movements", http.StatusInternalServerError)
+		return
+	}
+}
+
+// @Summary Transfer stock between warehouses
+// @Description roles with inventory:manage can move stock of a product, or of one of its variants, from one warehouse to another. The transfer is recorded as a pair of ledger entries sharing a transfer_id and is rejected when the source warehouse does not have enough on hand
+// @Tags inventory
+// @Accept json
+// @Produce json
+// @Param request body models.StockTransferRequest true "transfer"
+// @Success 201 {object} models.StockTransferResponse
+// @Failure 400 {object} string "Bad Request - Invalid input"
+// @Failure 401 {object} string "Unauthorized - Missing/invalid credentials"
+// @Failure 403 {object} string "Forbidden - Insufficient permissions"
+// @Failure 404 {object} string "Not Found - Resource doesn't exist"
+// @Failure 409 {object} string "Conflict - not enough stock in the source warehouse"
+// @Failure 500 {object} string "Internal Server Error"
+// @Router /api/v1/admin/stock-transfers [post]
+// @Security BearerAuth
+func (cfg *APIConfig) CreateStockTransferHandler(w http.ResponseWriter, r *http.Request) {
+	logger.Log.Info("entered create stock transfer handler")
+
+	userID, ok := r.Context().Value("userID").(uuid.UUID)
+	if !ok {
+		http.Error(w, "userID not in context", http.StatusUnauthorized)
+		return
+	}
+	var req models.StockTransferRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+	req.Reason = strings.TrimSpace(req.Reason)
+	switch {
+	case req.Quantity <= 0:
+		http.Error(w, "quantity of a transfer must be positive", http.StatusBadRequest)
+		return
+	case req.FromWarehouseID == req.ToWarehouseID:
+		http.Error(w, "a transfer needs two different warehouses", http.StatusBadRequest)
+		return
+	case utf8.RuneCountInString(req.Reason) > maxMovementReasonLength:
+		http.Error(w, fmt.Sprintf("reason must be at most %d characters", maxMovementReasonLength), http.StatusBadRequest)
+		return
+	}
+
+	if _, err := cfg.DB.GetProductByID(r.Context(), req.ProductID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			http.Error(w, "product not found", http.StatusNotFound)
+			return
+		}
+		logger.Log.Error("Failed to fetch product by ID", zap.String("productID", req.ProductID.String()), zap.Error(err))
+		http.Error(w, "databse operation failed", http.StatusInternalServerError)
+		return
+	}
+
+	out, in, err := cfg.recordStockTransfer(r.Context(), req, userID)
+	if err != nil {
+		writeStockError(w, err)
+		return
+	}
+	logger.Log.Info("stock transferred",
+		zap.String("productID", req.ProductID.String()),
+		zap.String("from", req.FromWarehouseID.String()),
+		zap.String("to", req.ToWarehouseID.String()),
+		zap.Int32("quantity", req.Quantity),
+		zap.String("userID", userID.String()),
+	)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	if err := json.NewEncoder(w).Encode(models.StockTransferResponse{
+		TransferID: out.TransferID.UUID,
+		Out:        stockMovementResponse(out),
+		In:         stockMovementResponse(in),
+	}); err != nil {
+		http.Error(w, "failed to encode stock transfer", http.StatusInternalServerError)
+		return
+	}
+}
+
+// movementDelta validates a movement request and returns the signed change
+// it makes to the on-hand quantity.
+func movementDelta(req *models.StockMovementRequest) (int32, error) {
+	req.Kind = strings.ToLower(strings.TrimSpace(req.Kind))
+	req.Reason = strings.TrimSpace(req.Reason)
+	if utf8.RuneCountInString(req.Reason) > maxMovementReasonLength {
+		return 0, fmt.Errorf("reason must be at most %d characters", maxMovementReasonLength)
+	}
+	switch req.Kind {
+	case "receive", "return":
+		if req.Quantity <= 0 {
+			return 0, fmt.Errorf("quantity of a %s must be positive", req.Kind)
+		}
+		return req.Quantity, nil
+	case "sell":
+		if req.Quantity <= 0 {
+			return 0, errors.New("quantity of a sell must be positive")
+		}
+		return -req.Quantity, nil
+	case "adjust":
+		if req.Quantity == 0 {
+			return 0, errors.New("quantity of an adjust must not be zero")
+		}
+		if req.Reason == "" {
+			return 0, errors.New("an adjust needs a reason")
+		}
+		return req.Quantity, nil
+	default:
+		return 0, errors.New("kind must be one of receive, sell, adjust, return")
+	}
+}
+
+// recordStockMovement applies a movement to the stock of a product, or of
+// its variant variantID, in a warehouse, the default one when warehouseID is
+// nil, and appends it to the ledger. The product is touched, since its
+// response carries the on-hand total.
+func (cfg *APIConfig) recordStockMovement(ctx context.Context, warehouseID, variantID *uuid.UUID, params database.CreateStockMovementParams) (database.StockMovement, error) {
+	movement, _, err := withTouchedProduct(ctx, cfg, params.ProductID, func(qtx *database.Queries) (database.StockMovement, error) {
+		var err error
+		params.WarehouseID, err = resolveWarehouse(ctx, qtx, warehouseID)
+		if err != nil {
+			return database.StockMovement{}, err
+		}
+		params.VariantID, err = lockVariant(ctx, qtx, params.ProductID, variantID)
+		if err != nil {
+			return database.StockMovement{}, err
+		}
+		if err := lockStockLevels(ctx, qtx, params.ProductID, params.VariantID, params.WarehouseID); err != nil {
+			return database.StockMovement{}, err
+		}
+		return applyStockMovement(ctx, qtx, params)
+	})
+	return movement, err
+}
+
+// recordStockTransfer moves stock between two warehouses as a pair of
+// movements, the one taking the stock out first. The product is touched
+// like for a single movement, its on-hand total stays the same but the
+// per-warehouse stock nested in it does not.
+func (cfg *APIConfig) recordStockTransfer(ctx context.Context, req models.StockTransferRequest, actorID uuid.UUID) (database.StockMovement, database.StockMovement, error) {
+	pair, _, err := withTouchedProduct(ctx, cfg, req.ProductID, func(qtx *database.Queries) ([2]database.StockMovement, error) {
+		var pair [2]database.StockMovement
+		for _, id := range []uuid.UUID{req.FromWarehouseID, req.ToWarehouseID} {
+			if _, err := resolveWarehouse(ctx, qtx, &id); err != nil {
+				return pair, err
+			}
+		}
+		variantID, err := lockVariant(ctx, qtx, req.ProductID, req.VariantID)
+		if err != nil {
+			return pair, err
+		}
+		if err := lockStockLevels(ctx, qtx, req.ProductID, variantID, req.FromWarehouseID, req.ToWarehouseID); err != nil {
+			return pair, err
+		}
+
+		params := database.CreateStockMovementParams{
+			ProductID:   req.ProductID,
+			WarehouseID: req.FromWarehouseID,
+			VariantID:   variantID,
+			Kind:        "transfer",
+			Quantity:    -req.Quantity,
+			Reason:      req.Reason,
+			ActorID:     uuid.NullUUID{UUID: actorID, Valid: true},
+			TransferID:  uuid.NullUUID{UUID: uuid.New(), Valid: true},
+		}
+		if pair[0], err = applyStockMovement(ctx, qtx, params); err != nil {
+			return pair, err
+		}
+		params.WarehouseID = req.ToWarehouseID
+		params.Quantity = req.Quantity
+		pair[1], err = applyStockMovement(ctx, qtx, params)
+		return pair, err
+	})
+	return pair[0], pair[1], err
+}
+
+// lockStockLevels locks the stock level rows of a product, or of one of its
+// variants, in the given warehouses, creating missing ones. The rows stay locked until the
+// transaction ends, so concurrent movements of the same stock are applied
+// one after the other and none of them can take it below zero. Rows are
+// locked in a fixed order so two transfers in opposite directions cannot
+// deadlock.
+func lockStockLevels(ctx context.Context, q *database.Queries, productID uuid.UUID, variantID uuid.NullUUID, warehouseIDs ...uuid.UUID) error {
+	slices.SortFunc(warehouseIDs, func(a, b uuid.UUID) int {
+		return bytes.Compare(a[:], b[:])
+	})
+	for _, warehouseID := range warehouseIDs {
+		if err := q.EnsureStockLevel(ctx, database.EnsureStockLevelParams{
+			ProductID:   productID,
+			WarehouseID: warehouseID,
+			VariantID:   variantID,
+		}); err != nil {
+			return err
+		}
+		if _, err := q.LockStockLevel(ctx, database.LockStockLevelParams{
+			ProductID:   productID,
+			WarehouseID: warehouseID,
+			VariantID:   variantID,
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// applyStockMovement updates a stock level locked by lockStockLevels and
+// appends the movement to the ledger.
+func applyStockMovement(ctx context.Context, q *database.Queries, params database.CreateStockMovementParams) (database.StockMovement, error) {
+	level, err := q.LockStockLevel(ctx, database.LockStockLevelParams{
+		ProductID:   params.ProductID,
+		WarehouseID: params.WarehouseID,
+		VariantID:   params.VariantID,
+	})
+	if err != nil {
+		return database.StockMovement{}, err
+	}
+	onHand := int64(level.OnHand) + int64(params.Quantity)
+	if onHand < 0 {
+		return database.StockMovement{}, fmt.Errorf("%w: %d on hand", errInsufficientStock, level.OnHand)
+	}
+	if onHand > math.MaxInt32 {
+		return database.StockMovement{}, errStockOverflow
+	}
+	if _, err := q.SetStockLevel(ctx, database.SetStockLevelParams{
+		ProductID:   params.ProductID,
+		WarehouseID: params.WarehouseID,
+		VariantID:   params.VariantID,
+		OnHand:      int32(onHand),
+	}); err != nil {
+		return database.StockMovement{}, err
+	}
+	params.BalanceAfter = int32(onHand)
+	return q.CreateStockMovement(ctx, params)
+}
+
+// resolveWarehouse returns the id of the given warehouse, or of the default
+// warehouse when id is nil.
+func resolveWarehouse(ctx context.Context, q *database.Queries, id *uuid.UUID) (uuid.UUID, error) {
+	var warehouse database.Warehouse
+	var err error
+	if id == nil {
+		warehouse, err = q.GetDefaultWarehouse(ctx)
+	} else {
+		warehouse, err = q.GetWarehouseByID(ctx, *id)
+	}
+	if errors.Is(err, sql.ErrNoRows) {
+		if id == nil {
+			return uuid.Nil, fmt.Errorf("%w: no default warehouse", errUnknownWarehouse)
+		}
+		return uuid.Nil, fmt.Errorf("%w: %s", errUnknownWarehouse, id)
+	}
+	if err != nil {
+		return uuid.Nil, err
+	}
+	return warehouse.ID, nil
+}
+
+// lockVariant checks variantID is a variant of the product and share locks
+// it until the transaction ends, so it is not deleted while its stock
+// moves. A nil variantID stands for the product itself.
+func lockVariant(ctx context.Context, q *database.Queries, productID uuid.UUID, variantID *uuid.UUID) (uuid.NullUUID, error) {
+	if variantID == nil {
+		return uuid.NullUUID{}, nil
+	}
+	_, err := q.ShareLockProductVariant(ctx, database.ShareLockProductVariantParams{
+		ID:        *variantID,
+		ProductID: productID,
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		return uuid.NullUUID{}, fmt.Errorf("%w: %s", errUnknownVariant, variantID)
+	}
+	if err != nil {
+		return uuid.NullUUID{}, err
+	}
+	return uuid.NullUUID{UUID: *variantID, Valid: true}, nil
+}
+
+func writeStockError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, errProductNotFound):
+		http.Error(w, err.Error(), http.StatusNotFound)
+	case errors.Is(err, errInsufficientStock):
+		http.Error(w, err.Error(), http.StatusConflict)
+	case errors.Is(err, errStockOverflow), errors.Is(err, errUnknownWarehouse), errors.Is(err, errUnknownVariant):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	default:
+		logger.Log.Error("failed to record stock movement", zap.Error(err))
+		http.Error(w, "databse operation failed", http.StatusInternalServerError)
+	}
+}
+
+func stockMovementResponse(movement database.StockMovement) models.StockMovementResponse {
+	resp := models.StockMovementResponse{
+		ID:           movement.ID,
+		ProductID:    movement.ProductID,
+		WarehouseID:  movement.WarehouseID,
+		Kind:         movement.Kind,
+		Quantity:     movement.Quantity,
+		Reason:       movement.Reason,
+		BalanceAfter: movement.BalanceAfter,
+		CreatedAt:    movement.CreatedAt,
+	}
+	if movement.ActorID.Valid {
+		resp.ActorID = &movement.ActorID.UUID
+	}
+	if movement.VariantID.Valid {
+		resp.VariantID = &movement.VariantID.UUID
+	}
+	if movement.TransferID.Valid {
+		resp.TransferID = &movement.TransferID.UUID
+	}
+	return resp
+}
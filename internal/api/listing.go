@@ -0,0 +1,243 @@
+package api
+
+import (
+	"database/sql"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/Black-tag/productAPI/internal/database"
+	"github.com/Black-tag/productAPI/internal/models"
+	"github.com/google/uuid"
+)
+
+const (
+	defaultPageLimit   = 20
+	maxPageLimit       = 100
+	defaultProductSort = "-created_at"
+)
+
+var productSorts = map[string]bool{
+	"created_at":  true,
+	"-created_at": true,
+	"updated_at":  true,
+	"-updated_at": true,
+	"price":       true,
+	"-price":      true,
+	"name":        true,
+	"-name":       true,
+}
+
+// productCursor is the position of the last product of a page. It carries
+// the sort it was issued for so a cursor cannot be replayed against a
+// different ordering.
+type productCursor struct {
+	Sort  string    `json:"s"`
+	Value string    `json:"v"`
+	ID    uuid.UUID `json:"id"`
+}
+
+func encodeProductCursor(sort string, product database.Product) string {
+	cursor := productCursor{Sort: sort, ID: product.ID}
+	switch strings.TrimPrefix(sort, "-") {
+	case "created_at":
+		cursor.Value = product.CreatedAt.Format(time.RFC3339Nano)
+	case "updated_at":
+		cursor.Value = product.UpdatedAt.Format(time.RFC3339Nano)
+	case "price":
+		cursor.Value = product.Price
+	case "name":
+		cursor.Value = product.Name
+	}
+	data, _ := json.Marshal(cursor)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func applyProductCursor(raw, sort string, params *database.ListProductsParams) error {
+	data, err := base64.RawURLEncoding.DecodeString(raw)
+	if err != nil {
+		return errors.New("invalid cursor")
+	}
+	var cursor productCursor
+	if err := json.Unmarshal(data, &cursor); err != nil {
+		return errors.New("invalid cursor")
+	}
+	if cursor.Sort != sort {
+		return errors.New("cursor does not match sort")
+	}
+
+	// the value is passed as the sort column's type, it must not be able to
+	// make the query fail
+	switch strings.TrimPrefix(sort, "-") {
+	case "created_at", "updated_at":
+		t, err := time.Parse(time.RFC3339Nano, cursor.Value)
+		if err != nil {
+			return errors.New("invalid cursor")
+		}
+		params.CursorTime = sql.NullTime{Time: t, Valid: true}
+	case "price":
+		// prices are stored as NUMERIC(10,2), ParseMoney accepts exactly
+		// those amounts and none of the hex, Inf or NaN forms ParseFloat does
+		price, err := models.ParseMoney(cursor.Value, models.DefaultCurrency)
+		if err != nil {
+			return errors.New("invalid cursor")
+		}
+		params.CursorPrice = sql.NullString{String: price.Amount(), Valid: true}
+	case "name":
+		params.CursorName = sql.NullString{String: cursor.Value, Valid: true}
+	}
+	params.CursorID = uuid.NullUUID{UUID: cursor.ID, Valid: true}
+	return nil
+}
+
+// parseProductListParams turns the listing query string into query params.
+// PageSize is one more than the requested limit so the handler can tell
+// whether another page exists.
+func parseProductListParams(r *http.Request) (database.ListProductsParams, int32, error) {
+	q := r.URL.Query()
+	params := database.ListProductsParams{Sort: defaultProductSort}
+
+	limit, err := parseLimit(r)
+	if err != nil {
+		return params, 0, err
+	}
+	params.PageSize = limit + 1
+
+	if v := q.Get("sort"); v != "" {
+		if !productSorts[v] {
+			return params, 0, fmt.Errorf("invalid sort %q", v)
+		}
+		params.Sort = v
+	}
+
+	if v := q.Get("name"); v != "" {
+		params.Name = sql.NullString{String: escapeLike(v), Valid: true}
+	}
+	for key, dst := range map[string]*sql.NullString{
+		"min_price": &params.MinPrice,
+		"max_price": &params.MaxPrice,
+	} {
+		v := q.Get(key)
+		if v == "" {
+			continue
+		}
+		price, err := models.ParseMoney(v, models.DefaultCurrency)
+		if err != nil {
+			return params, 0, fmt.Errorf("invalid %s: %w", key, err)
+		}
+		*dst = sql.NullString{String: price.Amount(), Valid: true}
+	}
+	if v := q.Get("posted_by"); v != "" {
+		postedBy, err := uuid.Parse(v)
+		if err != nil {
+			return params, 0, errors.New("invalid posted_by")
+		}
+		params.PostedBy = uuid.NullUUID{UUID: postedBy, Valid: true}
+	}
+	if v := q.Get("category"); v != "" {
+		categoryID, err := uuid.Parse(v)
+		if err != nil {
+			return params, 0, errors.New("invalid category")
+		}
+		params.CategoryID = uuid.NullUUID{UUID: categoryID, Valid: true}
+	}
+	if v := q.Get("include_descendants"); v != "" {
+		include, err := strconv.ParseBool(v)
+		if err != nil {
+			return params, 0, errors.New("invalid include_descendants")
+		}
+		params.IncludeDescendants = include
+	}
+	if len(q["tag"]) > 0 {
+		tags, err := normalizeTags(q["tag"])
+		if err != nil {
+			return params, 0, err
+		}
+		params.Tags = tags
+	}
+	switch q.Get("tag_match") {
+	case "", "all":
+		params.MatchAllTags = true
+	case "any":
+	default:
+		return params, 0, errors.New("invalid tag_match, expected any or all")
+	}
+	for key, dst := range map[string]*sql.NullTime{
+		"created_after":  &params.CreatedAfter,
+		"created_before": &params.CreatedBefore,
+		"updated_after":  &params.UpdatedAfter,
+		"updated_before": &params.UpdatedBefore,
+	} {
+		v := q.Get(key)
+		if v == "" {
+			continue
+		}
+		t, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			return params, 0, fmt.Errorf("invalid %s, expected RFC 3339 timestamp", key)
+		}
+		*dst = sql.NullTime{Time: t, Valid: true}
+	}
+
+	if v := q.Get("cursor"); v != "" {
+		if err := applyProductCursor(v, params.Sort, &params); err != nil {
+			return params, 0, err
+		}
+	}
+	return params, limit, nil
+}
+
+func parseLimit(r *http.Request) (int32, error) {
+	v := r.URL.Query().Get("limit")
+	if v == "" {
+		return defaultPageLimit, nil
+	}
+	limit, err := strconv.ParseInt(v, 10, 32)
+	if err != nil || limit < 1 {
+		return 0, errors.New("invalid limit")
+	}
+	if limit > maxPageLimit {
+		limit = maxPageLimit
+	}
+	return int32(limit), nil
+}
+
+func parsePagination(r *http.Request) (int32, int32, error) {
+	limit, err := parseLimit(r)
+	if err != nil {
+		return 0, 0, err
+	}
+	offset := int64(0)
+	if v := r.URL.Query().Get("offset"); v != "" {
+		offset, err = strconv.ParseInt(v, 10, 32)
+		if err != nil || offset < 0 {
+			return 0, 0, errors.New("invalid offset")
+		}
+	}
+	return limit, int32(offset), nil
+}
+
+// prefixTSQuery turns free text into a to_tsquery expression that matches
+// every word as a prefix, e.g. "red sho" becomes "red:* & sho:*". Anything
+// that is not a letter or digit is treated as a separator so user input can
+// never produce tsquery syntax errors.
+func prefixTSQuery(q string) string {
+	words := strings.FieldsFunc(q, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	for i, word := range words {
+		words[i] = word + ":*"
+	}
+	return strings.Join(words, " & ")
+}
+
+// escapeLike escapes the ILIKE wildcards so user input matches literally.
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
+}
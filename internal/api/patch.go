@@ -0,0 +1,311 @@
+package api
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/Black-tag/productAPI/internal/database"
+	"github.com/Black-tag/productAPI/internal/models"
+	"github.com/google/uuid"
+)
+
+const (
+	mergePatchContentType = "application/merge-patch+json"
+	jsonPatchContentType  = "application/json-patch+json"
+)
+
+// errUnprocessablePatch marks patches that are well formed but cannot be
+// applied to a product, they are answered with 422 instead of 400.
+var errUnprocessablePatch = errors.New("patch cannot be applied")
+
+// productDocument is the JSON representation patches are applied to. Only
+// the fields a client may change are part of it.
+type productDocument struct {
+	Name        string
+	SKU         string
+	GTIN        string
+	Price       models.Money
+	CategoryIDs []uuid.UUID
+	Tags        []string
+}
+
+func newProductDocument(current database.Product, relations *productRelations) productDocument {
+	doc := productDocument{
+		Name:        current.Name,
+		SKU:         current.Sku,
+		GTIN:        current.Gtin.String,
+		Price:       priceFromDB(current.Price, current.Currency),
+		CategoryIDs: make([]uuid.UUID, 0, len(relations.Categories)),
+		Tags:        relations.Tags,
+	}
+	for _, category := range relations.Categories {
+		doc.CategoryIDs = append(doc.CategoryIDs, category.ID)
+	}
+	return doc
+}
+
+// toPatchParams returns params that only set the columns that differ from
+// the stored product.
+func (doc productDocument) toPatchParams(current database.Product) database.PatchProductParams {
+	params := database.PatchProductParams{ID: current.ID}
+	if doc.Name != current.Name {
+		params.Name = sql.NullString{String: doc.Name, Valid: true}
+	}
+	if doc.Price != priceFromDB(current.Price, current.Currency) {
+		params.Price = sql.NullString{String: doc.Price.Amount(), Valid: true}
+		params.Currency = sql.NullString{String: doc.Price.Currency, Valid: true}
+	}
+	if doc.SKU != current.Sku {
+		params.Sku = sql.NullString{String: doc.SKU, Valid: true}
+	}
+	if doc.GTIN != current.Gtin.String {
+		params.Gtin = sql.NullString{String: doc.GTIN, Valid: doc.GTIN != ""}
+		params.ClearGtin = doc.GTIN == ""
+	}
+	return params
+}
+
+// applyMergePatch applies an RFC 7396 JSON Merge Patch. Members set to null
+// are removed, which only optional fields allow.
+func applyMergePatch(body []byte, doc productDocument) (productDocument, error) {
+
+	var patch map[string]json.RawMessage
+	if err := json.Unmarshal(body, &patch); err != nil {
+		return doc, errors.New("merge patch must be a JSON object")
+	}
+	for member, raw := range patch {
+		var err error
+		if string(bytes.TrimSpace(raw)) == "null" {
+			err = doc.remove(member)
+		} else {
+			err = doc.set(member, raw)
+		}
+		if err != nil {
+			return doc, err
+		}
+	}
+	return doc, nil
+}
+
+type jsonPatchOperation struct {
+	Op    string          `json:"op"`
+	Path  string          `json:"path"`
+	From  string          `json:"from"`
+	Value json.RawMessage `json:"value"`
+}
+
+// applyJSONPatch applies an RFC 6902 JSON Patch. Operations are applied in
+// order and the patch is atomic: if any operation fails nothing is written.
+func applyJSONPatch(body []byte, doc productDocument) (productDocument, error) {
+
+	var ops []jsonPatchOperation
+	if err := json.Unmarshal(body, &ops); err != nil {
+		return doc, errors.New("json patch must be an array of operations")
+	}
+	for i, op := range ops {
+		member, err := patchPathMember(op.Path)
+		if err != nil {
+			return doc, fmt.Errorf("operation %d: %w", i, err)
+		}
+		switch op.Op {
+		case "add", "replace":
+			if op.Value == nil {
+				return doc, fmt.Errorf("operation %d: value missing", i)
+			}
+			if err := doc.set(member, op.Value); err != nil {
+				return doc, fmt.Errorf("operation %d: %w", i, err)
+			}
+		case "test":
+			if op.Value == nil {
+				return doc, fmt.Errorf("operation %d: value missing", i)
+			}
+			ok, err := doc.equals(member, op.Value)
+			if err != nil {
+				return doc, fmt.Errorf("operation %d: %w", i, err)
+			}
+			if !ok {
+				return doc, fmt.Errorf("%w: operation %d: test failed for %s", errUnprocessablePatch, i, op.Path)
+			}
+		case "remove":
+			if err := doc.remove(member); err != nil {
+				return doc, fmt.Errorf("operation %d: %w", i, err)
+			}
+		case "move", "copy":
+			return doc, fmt.Errorf("%w: operation %d: %s is not supported on products", errUnprocessablePatch, i, op.Op)
+		default:
+			return doc, fmt.Errorf("operation %d: unknown op %q", i, op.Op)
+		}
+	}
+	return doc, nil
+}
+
+func patchPathMember(path string) (string, error) {
+	if !strings.HasPrefix(path, "/") || strings.Count(path, "/") != 1 {
+		return "", fmt.Errorf("%w: unsupported path %q", errUnprocessablePatch, path)
+	}
+	return path[1:], nil
+}
+
+func (doc *productDocument) set(member string, raw json.RawMessage) error {
+	switch member {
+	case "name":
+		var name string
+		if err := json.Unmarshal(raw, &name); err != nil || strings.TrimSpace(name) == "" {
+			return fmt.Errorf("%w: name must be a non-empty string", errUnprocessablePatch)
+		}
+		doc.Name = name
+	case "sku":
+		var sku string
+		if err := json.Unmarshal(raw, &sku); err != nil {
+			return fmt.Errorf("%w: %v", errUnprocessablePatch, models.ErrInvalidSKU)
+		}
+		sku, err := models.NormalizeSKU(sku)
+		if err != nil {
+			return fmt.Errorf("%w: %v", errUnprocessablePatch, err)
+		}
+		doc.SKU = sku
+	case "gtin":
+		var gtin string
+		if err := json.Unmarshal(raw, &gtin); err != nil {
+			return fmt.Errorf("%w: %v", errUnprocessablePatch, models.ErrInvalidGTIN)
+		}
+		gtin, err := models.NormalizeGTIN(gtin)
+		if err != nil {
+			return fmt.Errorf("%w: %v", errUnprocessablePatch, err)
+		}
+		doc.GTIN = gtin
+	case "price":
+		price, err := decodePatchPrice(raw)
+		if err != nil {
+			return err
+		}
+		doc.Price = price
+	case "category_ids":
+		var ids []uuid.UUID
+		if err := json.Unmarshal(raw, &ids); err != nil {
+			return fmt.Errorf("%w: category_ids must be a list of category ids", errUnprocessablePatch)
+		}
+		doc.CategoryIDs = ids
+	case "tags":
+		tags, err := decodePatchTags(raw)
+		if err != nil {
+			return err
+		}
+		doc.Tags = tags
+	default:
+		return fmt.Errorf("%w: unknown field %q", errUnprocessablePatch, member)
+	}
+	return nil
+}
+
+// remove clears an optional member. Required members cannot be removed.
+func (doc *productDocument) remove(member string) error {
+	switch member {
+	case "gtin":
+		doc.GTIN = ""
+	case "category_ids":
+		doc.CategoryIDs = []uuid.UUID{}
+	case "tags":
+		doc.Tags = []string{}
+	case "name", "sku", "price":
+		return fmt.Errorf("%w: %s cannot be removed", errUnprocessablePatch, member)
+	default:
+		return fmt.Errorf("%w: unknown field %q", errUnprocessablePatch, member)
+	}
+	return nil
+}
+
+func (doc *productDocument) equals(member string, raw json.RawMessage) (bool, error) {
+	switch member {
+	case "name":
+		var name string
+		if err := json.Unmarshal(raw, &name); err != nil {
+			return false, nil
+		}
+		return name == doc.Name, nil
+	case "sku":
+		var sku string
+		if err := json.Unmarshal(raw, &sku); err != nil {
+			return false, nil
+		}
+		sku, err := models.NormalizeSKU(sku)
+		return err == nil && sku == doc.SKU, nil
+	case "gtin":
+		var gtin string
+		if err := json.Unmarshal(raw, &gtin); err != nil {
+			return false, nil
+		}
+		gtin, err := models.NormalizeGTIN(gtin)
+		return err == nil && gtin == doc.GTIN, nil
+	case "price":
+		price, err := decodePatchPrice(raw)
+		if err != nil {
+			return false, nil
+		}
+		return price == doc.Price, nil
+	case "category_ids":
+		var ids []uuid.UUID
+		if err := json.Unmarshal(raw, &ids); err != nil {
+			return false, nil
+		}
+		return sameSet(ids, doc.CategoryIDs), nil
+	case "tags":
+		tags, err := decodePatchTags(raw)
+		if err != nil {
+			return false, nil
+		}
+		return sameSet(tags, doc.Tags), nil
+	default:
+		return false, fmt.Errorf("%w: unknown field %q", errUnprocessablePatch, member)
+	}
+}
+
+// decodePatchPrice accepts a price in any form models.Money accepts.
+func decodePatchPrice(raw json.RawMessage) (models.Money, error) {
+	var price models.Money
+	if err := json.Unmarshal(raw, &price); err != nil {
+		return price, fmt.Errorf("%w: %v", errUnprocessablePatch, err)
+	}
+	if err := validateProductPrice(price); err != nil {
+		return price, fmt.Errorf("%w: %v", errUnprocessablePatch, err)
+	}
+	return price, nil
+}
+
+// decodePatchTags accepts a list of tags, normalized like on create.
+func decodePatchTags(raw json.RawMessage) ([]string, error) {
+	var tags []string
+	if err := json.Unmarshal(raw, &tags); err != nil || tags == nil {
+		return nil, fmt.Errorf("%w: tags must be a list of strings", errUnprocessablePatch)
+	}
+	tags, err := normalizeTags(tags)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", errUnprocessablePatch, err)
+	}
+	return tags, nil
+}
+
+// sameSet reports whether a and b hold the same values, ignoring order and
+// duplicates.
+func sameSet[T comparable](a, b []T) bool {
+	set := make(map[T]bool, len(a))
+	for _, v := range a {
+		set[v] = false
+	}
+	for _, v := range b {
+		if _, ok := set[v]; !ok {
+			return false
+		}
+		set[v] = true
+	}
+	for _, seen := range set {
+		if !seen {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,28 @@
+package api
+
+import (
+	"errors"
+
+	"github.com/lib/pq"
+)
+
+// Postgres error codes the handlers answer with something other than 500.
+const (
+	pqForeignKeyViolation = "23503"
+	pqUniqueViolation     = "23505"
+)
+
+func isPQError(err error, code pq.ErrorCode) bool {
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) && pqErr.Code == code
+}
+
+// pqConstraint returns the constraint behind a Postgres error with the given
+// code, or "" for any other error.
+func pqConstraint(err error, code pq.ErrorCode) string {
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code == code {
+		return pqErr.Constraint
+	}
+	return ""
+}
@@ -0,0 +1,119 @@
+package api
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/Black-tag/productAPI/internal/database"
+	"github.com/Black-tag/productAPI/internal/logger"
+	"github.com/Black-tag/productAPI/internal/models"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// writeProduct runs a product write and records the resulting price in the
+// price history in the same transaction. The history only grows when the
+// price or currency actually changed. Errors from write are returned as is,
+// so callers can still tell sql.ErrNoRows apart.
+func (cfg *APIConfig) writeProduct(ctx context.Context, changedBy uuid.UUID, write func(*database.Queries) (database.Product, error)) (database.Product, error) {
+	tx, err := cfg.DBConn.BeginTx(ctx, nil)
+	if err != nil {
+		return database.Product{}, err
+	}
+	defer tx.Rollback()
+	qtx := cfg.txQueries(tx)
+
+	product, err := write(qtx)
+	if err != nil {
+		return database.Product{}, err
+	}
+	if err := qtx.RecordProductPrice(ctx, database.RecordProductPriceParams{
+		ProductID: product.ID,
+		Price:     product.Price,
+		Currency:  product.Currency,
+		ChangedBy: uuid.NullUUID{UUID: changedBy, Valid: true},
+		ChangedAt: product.UpdatedAt,
+	}); err != nil {
+		return database.Product{}, err
+	}
+	if err := tx.Commit(); err != nil {
+		return database.Product{}, err
+	}
+	return product, nil
+}
+
+// @Summary Get the price history of a product
+// @Description anyone can list the prices a product has had, newest first, with who set them. Each entry is in effect from changed_at until effective_until, the current price has no effective_until
+// @Tags products
+// @Produce json
+// @Param productID path string true "productID"
+// @Param limit query int false "page size (default 20, max 100)"
+// @Param offset query int false "number of entries to skip"
+// @Success 200 {object} models.ProductPriceHistoryResponse
+// @Failure 400 {object} string "Bad Request - Invalid input"
+// @Failure 404 {object} string "Not Found - Resource doesn't exist"
+// @Failure 500 {object} string "Internal Server Error"
+// @Router /api/v1/product/{productID}/prices [get]
+func (cfg *APIConfig) GetProductPriceHistoryHandler(w http.ResponseWriter, r *http.Request) {
+	logger.Log.Info("entered product price history handler")
+
+	productID, err := uuid.Parse(r.PathValue("productID"))
+	if err != nil {
+		http.Error(w, "Invalid product id", http.StatusBadRequest)
+		return
+	}
+	limit, offset, err := parsePagination(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if _, err := cfg.DB.GetProductByID(r.Context(), productID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			http.Error(w, "product not found", http.StatusNotFound)
+			return
+		}
+		logger.Log.Error("Failed to fetch product by ID", zap.String("productID", productID.String()), zap.Error(err))
+		http.Error(w, "databse operation failed", http.StatusInternalServerError)
+		return
+	}
+
+	rows, err := cfg.DB.ListProductPriceHistory(r.Context(), database.ListProductPriceHistoryParams{
+		ProductID:  productID,
+		PageSize:   limit,
+		PageOffset: offset,
+	})
+	if err != nil {
+		logger.Log.Error("failed to list price history", zap.String("productID", productID.String()), zap.Error(err))
+		http.Error(w, "databse operation failed", http.StatusInternalServerError)
+		return
+	}
+
+	respPayload := models.ProductPriceHistoryResponse{
+		Prices: make([]models.PriceHistoryEntry, 0, len(rows)),
+		Limit:  limit,
+		Offset: offset,
+	}
+	for _, row := range rows {
+		entry := models.PriceHistoryEntry{
+			Price:     priceFromDB(row.Price, row.Currency),
+			ChangedAt: row.ChangedAt,
+		}
+		if row.ChangedBy.Valid {
+			entry.ChangedBy = &row.ChangedBy.UUID
+		}
+		if row.EffectiveUntil.Valid {
+			entry.EffectiveUntil = &row.EffectiveUntil.Time
+		}
+		respPayload.Prices = append(respPayload.Prices, entry)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(respPayload); err != nil {
+		http.Error(w, "failed to encode price history", http.StatusInternalServerError)
+		return
+	}
+}
@@ -1,15 +1,20 @@
 package api
 
 import (
+	"database/sql"
 	"encoding/json"
-	"fmt"
+	"errors"
+	"io"
+	"mime"
 	"net/http"
 	
 	"strings"
 
 	"github.com/Black-tag/productAPI/internal/database"
 	"github.com/Black-tag/productAPI/internal/logger"
+	"github.com/Black-tag/productAPI/internal/metrics"
 	"github.com/Black-tag/productAPI/internal/models"
+	"github.com/Black-tag/productAPI/internal/permissions"
 	"github.com/google/uuid"
 	"go.uber.org/zap"
 )
@@ -20,9 +25,11 @@ import (
 // @Accept json
 // @Produce json
 // @Param request body models.ProductCreationRequest true "Product creation data"
-// @Success 201 {object} models.ProductCreationResponse
+// @Success 201 {object} models.ProductResponse
 // @Failure 400 {object} string "Bad Request - Invalid input"
+// @Failure 403 {object} string "Forbidden - Insufficient permissions"
 // @Failure 404 {object} string "Not Found - Resource doesn't exist"
+// @Failure 409 {object} string "Conflict - sku already used by another product or variant, or gtin by another product"
 // @Failure 500 {object} string "Internal Server Error"
 // @Router /api/v1/product [post]
 // @Security BearerAuth
@@ -39,27 +46,61 @@ func (cfg *APIConfig) ProductCreationHandler(w http.ResponseWriter, r *http.Requ
 
 	err := json.NewDecoder(r.Body).Decode(&req)
 	if err != nil {
-		http.Error(w, "Invalid request", http.StatusBadRequest)
+		http.Error(w, "Invalid request: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err := validateProductPrice(req.Price); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	tags, err := normalizeTags(req.Tags)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	sku, gtin, err := parseProductIdentifiers(req.SKU, req.GTIN)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
 	}
-	product, err := cfg.DB.CreateProductsFromRequest(r.Context(), database.CreateProductsFromRequestParams{
-		Name:     req.Name,
-		Price:    fmt.Sprintf("%.2f", req.Price),
-		PostedBy: userID,
+	product, err := cfg.writeProduct(r.Context(), userID, func(q *database.Queries) (database.Product, error) {
+		product, err := q.CreateProductsFromRequest(r.Context(), database.CreateProductsFromRequestParams{
+			Name:     req.Name,
+			Price:    req.Price.Amount(),
+			PostedBy: userID,
+			Currency: req.Price.Currency,
+			Sku:      sku,
+			Gtin:     gtin,
+		})
+		if err != nil {
+			return product, err
+		}
+		if err := setProductCategories(r.Context(), q, product.ID, req.CategoryIDs); err != nil {
+			return product, err
+		}
+		return product, setProductTags(r.Context(), q, product.ID, tags)
 	})
+	if errors.Is(err, errUnknownCategory) {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if writeProductConflict(w, err) {
+		return
+	}
 	if err != nil {
+		logger.Log.Error("Failed to create product", zap.Error(err))
 		http.Error(w, "databse operation to create product failed", http.StatusInternalServerError)
 		return
 	}
+	metrics.ProductsCreated.Inc()
 
-	data := models.ProductCreationResponse{
-		ID:        product.ID,
-		Name:      product.Name,
-		Price:     product.Price,
-		CreatedAt: product.CreatedAt,
-		UpdatedAt: product.UpdatedAt,
-		PostedBy:  product.PostedBy,
+	data := productResponse(product)
+	if err := cfg.attachRelations(r.Context(), []*models.ProductResponse{&data}); err != nil {
+		logger.Log.Error("failed to load product categories and tags", zap.Error(err))
+		http.Error(w, "databse operation failed", http.StatusInternalServerError)
+		return
 	}
+	setValidators(w, product)
 	w.Header().Set("Content-Type", "application/json")
 	if err := json.NewEncoder(w).Encode(data); err != nil {
 		http.Error(w, "failed to encode products", http.StatusInternalServerError)
@@ -68,43 +109,288 @@ func (cfg *APIConfig) ProductCreationHandler(w http.ResponseWriter, r *http.Requ
 	}
 
 }
-// @Summary Get existing  products
-// @Description  users can get all existing Products
+// @Summary List products
+// @Description users can list products page by page, filtered and sorted. Pass next_cursor back as cursor to fetch the following page
 // @Tags products
 // @Accept json
 // @Produce json
-// @Success 200 {object} database.Product
+// @Param limit query int false "page size (default 20, max 100)"
+// @Param cursor query string false "next_cursor from the previous page"
+// @Param sort query string false "created_at, updated_at, price or name, prefix with - for descending (default -created_at)"
+// @Param name query string false "case-insensitive name substring"
+// @Param min_price query number false "minimum price, compared with the amount in each product's own currency"
+// @Param max_price query number false "maximum price, compared with the amount in each product's own currency"
+// @Param posted_by query string false "id of the user who posted the product"
+// @Param category query string false "id of a category the product is in"
+// @Param include_descendants query bool false "with category, also match products in any of its descendants"
+// @Param tag query []string false "tag the product carries, repeat for several" collectionFormat(multi)
+// @Param tag_match query string false "all (default) to require every tag, any to require at least one"
+// @Param created_after query string false "RFC 3339 timestamp, inclusive"
+// @Param created_before query string false "RFC 3339 timestamp, exclusive"
+// @Param updated_after query string false "RFC 3339 timestamp, inclusive"
+// @Param updated_before query string false "RFC 3339 timestamp, exclusive"
+// @Param currency query string false "also report prices converted to this currency code, rounded to the nearest minor unit with halves away from zero"
+// @Success 200 {object} models.ProductListResponse
 // @Failure 400 {object} string "Bad Request - Invalid input"
+// @Failure 422 {object} string "No exchange rate for the requested currency, or the converted price is too large"
 // @Failure 500 {object} string "Internal Server Error"
 // @Router /api/v1/product [get]
-// @Security BearerAuth
 func (cfg *APIConfig) GetProductsHandler(w http.ResponseWriter, r *http.Request) {
 	logger.Log.Info("entered get products handler")
 
-	products, err := cfg.DB.GetAllProducts(r.Context())
+	params, limit, err := parseProductListParams(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	converter, err := cfg.newPriceConverter(r)
+	if err != nil {
+		writeConversionError(w, err)
+		return
+	}
+
+	rows, err := cfg.DB.ListProducts(r.Context(), params)
 	if err != nil {
+		logger.Log.Error("failed to list products", zap.Error(err))
 		http.Error(w, "databse opertaion to get products failed", http.StatusInternalServerError)
 		return
 	}
+	products := make([]database.Product, 0, len(rows))
+	for _, row := range rows {
+		products = append(products, database.Product(row))
+	}
 
-	if len(products) == 0 {
-		http.Error(w, "no products in databse", http.StatusNoContent)
-		w.Header().Set("Content-Type", "application/json")
-    	w.Write([]byte("[]"))
+	respPayload := models.ProductListResponse{
+		Products: make([]models.ProductResponse, 0, len(products)),
+	}
+	if len(products) > int(limit) {
+		products = products[:limit]
+		respPayload.NextCursor = encodeProductCursor(params.Sort, products[len(products)-1])
+	}
+	for _, product := range products {
+		item := productResponse(product)
+		if err := converter.apply(&item); err != nil {
+			writeConversionError(w, err)
+			return
+		}
+		respPayload.Products = append(respPayload.Products, item)
+	}
+	items := make([]*models.ProductResponse, 0, len(respPayload.Products))
+	for i := range respPayload.Products {
+		items = append(items, &respPayload.Products[i])
+	}
+	if err := cfg.attachRelations(r.Context(), items); err != nil {
+		logger.Log.Error("failed to load product categories and tags", zap.Error(err))
+		http.Error(w, "databse operation failed", http.StatusInternalServerError)
 		return
 	}
-	
+
 	w.Header().Set("Content-Type", "application/json")
 
-	if err := json.NewEncoder(w).Encode(products); err != nil {
+	if err := json.NewEncoder(w).Encode(respPayload); err != nil {
 		http.Error(w, "failed to encode products", http.StatusInternalServerError)
 		return
 	}
 }
 
+// @Summary Get a product
+// @Description anyone can fetch a single product. Responses carry ETag and Last-Modified headers and conditional requests are answered with 304
+// @Tags products
+// @Produce json
+// @Param productID path string true "productID"
+// @Param If-None-Match header string false "ETag from a previous response"
+// @Param If-Modified-Since header string false "Last-Modified from a previous response"
+// @Param currency query string false "also report prices converted to this currency code, rounded to the nearest minor unit with halves away from zero"
+// @Success 200 {object} models.ProductResponse
+// @Success 304 {string} string "Not Modified"
+// @Failure 400 {object} string "Bad Request - Invalid input"
+// @Failure 404 {object} string "Not Found - Resource doesn't exist"
+// @Failure 422 {object} string "No exchange rate for the requested currency, or the converted price is too large"
+// @Failure 500 {object} string "Internal Server Error"
+// @Router /api/v1/product/{productID} [get]
+func (cfg *APIConfig) GetProductHandler(w http.ResponseWriter, r *http.Request) {
+	logger.Log.Info("entered get product handler")
+
+	productID, err := uuid.Parse(r.PathValue("productID"))
+	if err != nil {
+		http.Error(w, "Invalid product id", http.StatusBadRequest)
+		return
+	}
+
+	product, err := cfg.DB.GetProductByID(r.Context(), productID)
+	if errors.Is(err, sql.ErrNoRows) {
+		http.Error(w, "product not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		logger.Log.Error("Failed to fetch product by ID", zap.String("productID", productID.String()), zap.Error(err))
+		http.Error(w, "databse operation failed", http.StatusInternalServerError)
+		return
+	}
+
+	cfg.writeProductResponse(w, r, product)
+}
+
+// @Summary Get a product by SKU
+// @Description anyone can fetch a single product by its SKU, matched without regard to case. A variant SKU returns the product the variant belongs to. Conditional requests work as for the product id
+// @Tags products
+// @Produce json
+// @Param sku path string true "SKU"
+// @Param If-None-Match header string false "ETag from a previous response"
+// @Param If-Modified-Since header string false "Last-Modified from a previous response"
+// @Param currency query string false "also report prices converted to this currency code, rounded to the nearest minor unit with halves away from zero"
+// @Success 200 {object} models.ProductResponse
+// @Success 304 {string} string "Not Modified"
+// @Failure 400 {object} string "Bad Request - Invalid input"
+// @Failure 404 {object} string "Not Found - Resource doesn't exist"
+// @Failure 422 {object} string "No exchange rate for the requested currency, or the converted price is too large"
+// @Failure 500 {object} string "Internal Server Error"
+// @Router /api/v1/product/by-sku/{sku} [get]
+func (cfg *APIConfig) GetProductBySKUHandler(w http.ResponseWriter, r *http.Request) {
+	logger.Log.Info("entered get product by sku handler")
+
+	sku, err := models.NormalizeSKU(r.PathValue("sku"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	product, err := cfg.DB.GetProductBySKU(r.Context(), sku)
+	if errors.Is(err, sql.ErrNoRows) {
+		http.Error(w, "product not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		logger.Log.Error("Failed to fetch product by sku", zap.String("sku", sku), zap.Error(err))
+		http.Error(w, "databse operation failed", http.StatusInternalServerError)
+		return
+	}
+
+	cfg.writeProductResponse(w, r, product)
+}
+
+// writeProductResponse answers a read of a single product.
+func (cfg *APIConfig) writeProductResponse(w http.ResponseWriter, r *http.Request, product database.Product) {
+	converter, err := cfg.newPriceConverter(r)
+	if err != nil {
+		writeConversionError(w, err)
+		return
+	}
+
+	setValidators(w, product)
+	// a converted price changes with the exchange rate, not the product
+	// version, so only unconverted responses can be revalidated with 304
+	if converter == nil && notModified(r, productETag(product), product.UpdatedAt) {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+
+	respPayload := productResponse(product)
+	if err := converter.apply(&respPayload); err != nil {
+		writeConversionError(w, err)
+		return
+	}
+	if err := cfg.attachRelations(r.Context(), []*models.ProductResponse{&respPayload}); err != nil {
+		logger.Log.Error("failed to load product categories and tags", zap.Error(err))
+		http.Error(w, "databse operation failed", http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(respPayload); err != nil {
+		http.Error(w, "failed to encode product", http.StatusInternalServerError)
+		return
+	}
+}
+
+// @Summary Search products
+// @Description full-text search over product names ranked by relevance. Every word is matched as a prefix. The snippet is the HTML-escaped name with matches wrapped in <mark> tags, the only markup it contains
+// @Tags products
+// @Produce json
+// @Param q query string true "search text"
+// @Param limit query int false "page size (default 20, max 100)"
+// @Param offset query int false "number of results to skip"
+// @Param currency query string false "also report prices converted to this currency code, rounded to the nearest minor unit with halves away from zero"
+// @Success 200 {object} models.ProductSearchResponse
+// @Failure 400 {object} string "Bad Request - Invalid input"
+// @Failure 422 {object} string "No exchange rate for the requested currency, or the converted price is too large"
+// @Failure 500 {object} string "Internal Server Error"
+// @Router /api/v1/product/search [get]
+func (cfg *APIConfig) SearchProductsHandler(w http.ResponseWriter, r *http.Request) {
+	logger.Log.Info("entered search products handler")
+
+	query := prefixTSQuery(r.URL.Query().Get("q"))
+	if query == "" {
+		http.Error(w, "search text missing", http.StatusBadRequest)
+		return
+	}
+	limit, offset, err := parsePagination(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	converter, err := cfg.newPriceConverter(r)
+	if err != nil {
+		writeConversionError(w, err)
+		return
+	}
+
+	rows, err := cfg.DB.SearchProducts(r.Context(), database.SearchProductsParams{
+		Query:      query,
+		PageSize:   limit,
+		PageOffset: offset,
+	})
+	if err != nil {
+		logger.Log.Error("failed to search products", zap.String("query", query), zap.Error(err))
+		http.Error(w, "databse opertaion to search products failed", http.StatusInternalServerError)
+		return
+	}
+
+	respPayload := models.ProductSearchResponse{
+		Results: make([]models.ProductSearchResult, 0, len(rows)),
+		Limit:   limit,
+		Offset:  offset,
+	}
+	for _, row := range rows {
+		result := models.ProductSearchResult{
+			ProductResponse: models.ProductResponse{
+				ID:        row.ID,
+				Name:      row.Name,
+				SKU:       row.Sku,
+				GTIN:      row.Gtin.String,
+				Price:     priceFromDB(row.Price, row.Currency),
+				CreatedAt: row.CreatedAt,
+				UpdatedAt: row.UpdatedAt,
+				PostedBy:  row.PostedBy,
+				Version:   row.Version,
+			},
+			Rank:    row.Rank,
+			Snippet: row.Snippet,
+		}
+		if err := converter.apply(&result.ProductResponse); err != nil {
+			writeConversionError(w, err)
+			return
+		}
+		respPayload.Results = append(respPayload.Results, result)
+	}
+	items := make([]*models.ProductResponse, 0, len(respPayload.Results))
+	for i := range respPayload.Results {
+		items = append(items, &respPayload.Results[i].ProductResponse)
+	}
+	if err := cfg.attachRelations(r.Context(), items); err != nil {
+		logger.Log.Error("failed to load product categories and tags", zap.Error(err))
+		http.Error(w, "databse operation failed", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(respPayload); err != nil {
+		http.Error(w, "failed to encode products", http.StatusInternalServerError)
+		return
+	}
+}
 
 // @Summary Delete an existing  product
-// @Description admin can delete product using their id
+// @Description owners can delete their product, roles with product:delete:any can delete any product. Deleted products move to the trash, where they can be restored until they are purged
 // @Tags products
 // @Accept json
 // @Produce json
@@ -112,8 +398,11 @@ func (cfg *APIConfig) GetProductsHandler(w http.ResponseWriter, r *http.Request)
 // @Failure 400 {object} string "Bad Request - Invalid input"
 // @Failure 401 {object} string "Unauthorized - Missing/invalid credentials"
 // @Failure 403 {object} string "Forbidden - Insufficient permissions"
+// @Failure 412 {object} string "Precondition Failed - product has been modified"
+// @Failure 428 {object} string "Precondition Required - If-Match header missing"
 // @Failure 500 {object} string "Internal Server Error"
 // @Param productID path string true "ProductID" 
+// @Param If-Match header string false "ETag of the product being deleted"
 // @Router /api/v1/product/{productID} [delete]
 // @Security BearerAuth
 func (cfg *APIConfig) DeleteProductHandler(w http.ResponseWriter, r *http.Request) {
@@ -127,8 +416,6 @@ func (cfg *APIConfig) DeleteProductHandler(w http.ResponseWriter, r *http.Reques
 		return
 	}
 
-	userRole := r.Context().Value("role")
-
 	productID, err := uuid.Parse(productIdStr)
 	if err != nil {
 		http.Error(w, "Invalid product id", http.StatusBadRequest)
@@ -141,17 +428,30 @@ func (cfg *APIConfig) DeleteProductHandler(w http.ResponseWriter, r *http.Reques
 		http.Error(w, "product not found", http.StatusNotFound)
 		return
 	}
-	if userID != product.PostedBy && userRole != "admin" {
-		http.Error(w, "unauthorised for deleting", http.StatusUnauthorized)
+	perms := permissions.FromContext(r.Context())
+	if !perms.CanModify(userID, product.PostedBy, permissions.ProductDeleteOwn, permissions.ProductDeleteAny) {
+		http.Error(w, "forbidden to delete product", http.StatusForbidden)
 		return
 
 	}
+	expectedVersion, ok := cfg.checkIfMatch(w, r, product)
+	if !ok {
+		return
+	}
 
-	err = cfg.DB.DeleteProductByID(r.Context(), productID)
+	deleted, err := cfg.DB.TrashProductByID(r.Context(), database.TrashProductByIDParams{
+		ID:              productID,
+		DeletedBy:       uuid.NullUUID{UUID: userID, Valid: true},
+		ExpectedVersion: expectedVersion,
+	})
 	if err != nil {
 		http.Error(w, "databse deletion failed", http.StatusInternalServerError)
 		return
 	}
+	if deleted == 0 {
+		writeLostWrite(w, expectedVersion)
+		return
+	}
 	w.WriteHeader(http.StatusNoContent)
 
 }
@@ -163,9 +463,14 @@ func (cfg *APIConfig) DeleteProductHandler(w http.ResponseWriter, r *http.Reques
 // @Produce json
 // @Param bugid path string true "productID" example:"87f0ea02-7b24-41bd-8418-0831a019fc87"
 // @Param request body models.UpdateProductRequest true "product updation data"
-// @Success 200 {object} models.UpdatedProductResponse
+// @Param If-Match header string false "ETag of the product being updated"
+// @Success 200 {object} models.ProductResponse
 // @Failure 400 {object} string "Bad Request - Invalid input"
 // @Failure 401 {object} string "Unauthorized - Missing/invalid credentials"
+// @Failure 403 {object} string "Forbidden - Insufficient permissions"
+// @Failure 412 {object} string "Precondition Failed - product has been modified"
+// @Failure 428 {object} string "Precondition Required - If-Match header missing"
+// @Failure 409 {object} string "Conflict - sku already used by another product or variant, or gtin by another product"
 // @Failure 500 {object} string "Internal Server Error"
 // @Router /api/v1/product/{productID} [put]
 // @Security BearerAuth
@@ -178,12 +483,6 @@ func (cfg *APIConfig) UpdateProductsHandler(w http.ResponseWriter, r *http.Reque
 		http.Error(w, "userID not in context ", http.StatusUnauthorized)
 		return
 	}
-	userroleINterface := r.Context().Value("role")
-	userrole, ok := userroleINterface.(string)
-	if !ok {
-		http.Error(w, "invalid role in context", http.StatusInternalServerError)
-		return
-	}
 
 	// productIDStr := r.PathValue("productID")
 	productIDStr := strings.TrimPrefix(r.URL.Path, "/api/v1/product/")
@@ -207,16 +506,25 @@ func (cfg *APIConfig) UpdateProductsHandler(w http.ResponseWriter, r *http.Reque
 	var req models.UpdateProductRequest
 	err = json.NewDecoder(r.Body).Decode(&req)
 	if err != nil {
-		http.Error(w, "cannot decode request", http.StatusInternalServerError)
+		http.Error(w, "cannot decode request: "+err.Error(), http.StatusBadRequest)
 		return
 
 	}
-	// priceFloat, err := strconv.ParseFloat(req.Price, 64)
-	// if err != nil {
-	// 	http.Error(w, "invalid price format", http.StatusBadRequest)
-	// 	return
-	// }
-	// priceStr := fmt.Sprintf("%.2f", priceFloat)
+	if err := validateProductPrice(req.Price); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	tags, err := normalizeTags(req.Tags)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	sku, gtin, err := parseProductIdentifiers(req.SKU, req.GTIN)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
 
 	product, err := cfg.DB.GetProductByID(r.Context(), productID)
 	if err != nil {
@@ -224,40 +532,294 @@ func (cfg *APIConfig) UpdateProductsHandler(w http.ResponseWriter, r *http.Reque
 		http.Error(w, "product not found", http.StatusNotFound)
 		return
 	}
-	if userID != product.PostedBy && userrole != "admin" {
-		http.Error(w, "unauthorized for edit product", http.StatusUnauthorized)
+	perms := permissions.FromContext(r.Context())
+	if !perms.CanModify(userID, product.PostedBy, permissions.ProductUpdateOwn, permissions.ProductUpdateAny) {
+		http.Error(w, "forbidden to edit product", http.StatusForbidden)
 		return
 
 	}
-	priceStr := fmt.Sprintf("%.2f", req.Price)
-	updatedProduct, err := cfg.DB.UpdateProduct(r.Context(), database.UpdateProductParams{
-		ID:    productID,
-		Name:  req.Name,
-		Price: priceStr,
+	expectedVersion, ok := cfg.checkIfMatch(w, r, product)
+	if !ok {
+		return
+	}
+	updatedProduct, err := cfg.writeProduct(r.Context(), userID, func(q *database.Queries) (database.Product, error) {
+		product, err := q.UpdateProduct(r.Context(), database.UpdateProductParams{
+			ID:              productID,
+			Name:            req.Name,
+			Price:           req.Price.Amount(),
+			Currency:        req.Price.Currency,
+			Sku:             sku,
+			Gtin:            gtin,
+			ExpectedVersion: expectedVersion,
+		})
+		if err != nil {
+			return product, err
+		}
+		if req.CategoryIDs != nil {
+			if err := setProductCategories(r.Context(), q, product.ID, req.CategoryIDs); err != nil {
+				return product, err
+			}
+		}
+		if tags != nil {
+			if err := setProductTags(r.Context(), q, product.ID, tags); err != nil {
+				return product, err
+			}
+		}
+		return product, nil
 	})
+	if errors.Is(err, sql.ErrNoRows) {
+		writeLostWrite(w, expectedVersion)
+		return
+	}
+	if errors.Is(err, errUnknownCategory) {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if writeProductConflict(w, err) {
+		return
+	}
 	if err != nil {
 		 logger.Log.Error("Failed to update product", 
         zap.String("productID", productID.String()), 
         zap.String("name", req.Name), 
-        zap.String("price", req.Price),
+        zap.String("price", req.Price.String()),
         zap.Error(err),
     )
 		logger.Log.Error("Failed to update product", zap.String("productID", productID.String()), zap.Error(err))
 		http.Error(w, "databse operation failed", http.StatusInternalServerError)
 		return
 	}
-
-	respPayload := models.UpdatedProductResponse{
-		ID:        updatedProduct.ID,
-		Name:      updatedProduct.Name,
-		Price:     updatedProduct.Price,
-		CreatedAt: updatedProduct.CreatedAt,
-		UpdatedAt: updatedProduct.UpdatedAt,
-		PostedBy:  updatedProduct.PostedBy,
+	respPayload := productResponse(updatedProduct)
+	if err := cfg.attachRelations(r.Context(), []*models.ProductResponse{&respPayload}); err != nil {
+		logger.Log.Error("failed to load product categories and tags", zap.Error(err))
+		http.Error(w, "databse operation failed", http.StatusInternalServerError)
+		return
 	}
 
+	setValidators(w, updatedProduct)
 	if err := json.NewEncoder(w).Encode(respPayload); err != nil {
 		http.Error(w, "failed to encode products", http.StatusInternalServerError)
 		return
 	}
 }
+
+// @Summary Partially update a product
+// @Description owners (or roles with product:update:any) can change individual fields with a JSON Merge Patch (RFC 7396) or a JSON Patch (RFC 6902) document, selected by Content-Type
+// @Tags products
+// @Accept application/merge-patch+json
+// @Accept application/json-patch+json
+// @Produce json
+// @Param productID path string true "productID"
+// @Param request body object true "merge patch object or json patch operations"
+// @Param If-Match header string false "ETag of the product being patched"
+// @Success 200 {object} models.ProductResponse
+// @Failure 400 {object} string "Bad Request - Invalid input"
+// @Failure 401 {object} string "Unauthorized - Missing/invalid credentials"
+// @Failure 403 {object} string "Forbidden - Insufficient permissions"
+// @Failure 404 {object} string "Not Found - Resource doesn't exist"
+// @Failure 415 {object} string "Unsupported patch format"
+// @Failure 412 {object} string "Precondition Failed - product has been modified"
+// @Failure 422 {object} string "Patch cannot be applied to the product"
+// @Failure 428 {object} string "Precondition Required - If-Match header missing"
+// @Failure 409 {object} string "Conflict - sku already used by another product or variant, or gtin by another product"
+// @Failure 500 {object} string "Internal Server Error"
+// @Router /api/v1/product/{productID} [patch]
+// @Security BearerAuth
+func (cfg *APIConfig) PatchProductHandler(w http.ResponseWriter, r *http.Request) {
+	logger.Log.Info("entered patch product handler")
+
+	userID, ok := r.Context().Value("userID").(uuid.UUID)
+	if !ok {
+		http.Error(w, "userID not in context", http.StatusUnauthorized)
+		return
+	}
+
+	productID, err := uuid.Parse(r.PathValue("productID"))
+	if err != nil {
+		http.Error(w, "Invalid product id", http.StatusBadRequest)
+		return
+	}
+
+	contentType, _, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if err != nil || (contentType != mergePatchContentType && contentType != jsonPatchContentType) {
+		w.Header().Set("Accept-Patch", mergePatchContentType+", "+jsonPatchContentType)
+		http.Error(w, "unsupported patch content type", http.StatusUnsupportedMediaType)
+		return
+	}
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, "cannot read request", http.StatusBadRequest)
+		return
+	}
+
+	product, err := cfg.DB.GetProductByID(r.Context(), productID)
+	if errors.Is(err, sql.ErrNoRows) {
+		http.Error(w, "product not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		logger.Log.Error("Failed to fetch product by ID", zap.String("productID", productID.String()), zap.Error(err))
+		http.Error(w, "databse operation failed", http.StatusInternalServerError)
+		return
+	}
+	perms := permissions.FromContext(r.Context())
+	if !perms.CanModify(userID, product.PostedBy, permissions.ProductUpdateOwn, permissions.ProductUpdateAny) {
+		http.Error(w, "forbidden to edit product", http.StatusForbidden)
+		return
+	}
+
+	expectedVersion, ok := cfg.checkIfMatch(w, r, product)
+	if !ok {
+		return
+	}
+
+	relations, err := cfg.loadProductRelations(r.Context(), []uuid.UUID{product.ID})
+	if err != nil {
+		logger.Log.Error("failed to load product categories and tags", zap.Error(err))
+		http.Error(w, "databse operation failed", http.StatusInternalServerError)
+		return
+	}
+	current := newProductDocument(product, relations[product.ID])
+
+	var doc productDocument
+	if contentType == mergePatchContentType {
+		doc, err = applyMergePatch(body, current)
+	} else {
+		doc, err = applyJSONPatch(body, current)
+	}
+	if errors.Is(err, errUnprocessablePatch) {
+		http.Error(w, err.Error(), http.StatusUnprocessableEntity)
+		return
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	params := doc.toPatchParams(product)
+	params.ExpectedVersion = expectedVersion
+	categoriesChanged := !sameSet(doc.CategoryIDs, current.CategoryIDs)
+	tagsChanged := !sameSet(doc.Tags, current.Tags)
+	if params.Name.Valid || params.Price.Valid || params.Sku.Valid || params.Gtin.Valid || params.ClearGtin || categoriesChanged || tagsChanged {
+		product, err = cfg.writeProduct(r.Context(), userID, func(q *database.Queries) (database.Product, error) {
+			product, err := q.PatchProduct(r.Context(), params)
+			if err != nil {
+				return product, err
+			}
+			if categoriesChanged {
+				if err := setProductCategories(r.Context(), q, product.ID, doc.CategoryIDs); err != nil {
+					return product, err
+				}
+			}
+			if tagsChanged {
+				if err := setProductTags(r.Context(), q, product.ID, doc.Tags); err != nil {
+					return product, err
+				}
+			}
+			return product, nil
+		})
+		if errors.Is(err, sql.ErrNoRows) {
+			writeLostWrite(w, expectedVersion)
+			return
+		}
+		if errors.Is(err, errUnknownCategory) {
+			http.Error(w, err.Error(), http.StatusUnprocessableEntity)
+			return
+		}
+		if writeProductConflict(w, err) {
+			return
+		}
+		if err != nil {
+			logger.Log.Error("Failed to patch product", zap.String("productID", productID.String()), zap.Error(err))
+			http.Error(w, "databse operation failed", http.StatusInternalServerError)
+			return
+		}
+	}
+
+	respPayload := productResponse(product)
+	if err := cfg.attachRelations(r.Context(), []*models.ProductResponse{&respPayload}); err != nil {
+		logger.Log.Error("failed to load product categories and tags", zap.Error(err))
+		http.Error(w, "databse operation failed", http.StatusInternalServerError)
+		return
+	}
+	setValidators(w, product)
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(respPayload); err != nil {
+		http.Error(w, "failed to encode product", http.StatusInternalServerError)
+		return
+	}
+}
+
+// validateProductPrice checks a decoded request price. A zero Money means
+// the price member was missing from the request.
+func validateProductPrice(price models.Money) error {
+	if price.Currency == "" {
+		return errors.New("price is required")
+	}
+	return price.Validate()
+}
+
+// parseProductIdentifiers normalizes the SKU and GTIN of a request, empty
+// values stay unset.
+func parseProductIdentifiers(sku, gtin string) (sql.NullString, sql.NullString, error) {
+	var skuParam, gtinParam sql.NullString
+	if sku != "" {
+		normalized, err := models.NormalizeSKU(sku)
+		if err != nil {
+			return skuParam, gtinParam, err
+		}
+		skuParam = sql.NullString{String: normalized, Valid: true}
+	}
+	if gtin != "" {
+		normalized, err := models.NormalizeGTIN(gtin)
+		if err != nil {
+			return skuParam, gtinParam, err
+		}
+		gtinParam = sql.NullString{String: normalized, Valid: true}
+	}
+	return skuParam, gtinParam, nil
+}
+
+// writeProductConflict answers writes that would give a product the SKU or
+// GTIN of another one, including products in the trash, or the SKU of a
+// variant. It reports whether err was such a conflict.
+func writeProductConflict(w http.ResponseWriter, err error) bool {
+	switch pqConstraint(err, pqUniqueViolation) {
+	case "products_sku_key":
+		http.Error(w, "a product with this sku already exists", http.StatusConflict)
+	case "products_gtin_key":
+		http.Error(w, "a product with this gtin already exists", http.StatusConflict)
+	case "skus_pkey":
+		http.Error(w, "sku already used by another product or variant", http.StatusConflict)
+	default:
+		return false
+	}
+	return true
+}
+
+// priceFromDB reads a products.price value. NUMERIC(10,2) always holds a
+// valid amount, so a parse failure means the schema and Money disagree.
+func priceFromDB(price, currency string) models.Money {
+	m, err := models.ParseMoney(price, currency)
+	if err != nil {
+		logger.Log.Error("cannot parse price from database", zap.String("price", price), zap.Error(err))
+	}
+	return m
+}
+
+// productResponse maps a stored product to its response, without any
+// converted price.
+func productResponse(product database.Product) models.ProductResponse {
+	return models.ProductResponse{
+		ID:        product.ID,
+		Name:      product.Name,
+		SKU:       product.Sku,
+		GTIN:      product.Gtin.String,
+		Price:     priceFromDB(product.Price, product.Currency),
+		CreatedAt: product.CreatedAt,
+		UpdatedAt: product.UpdatedAt,
+		PostedBy:  product.PostedBy,
+		Version:   product.Version,
+	}
+}
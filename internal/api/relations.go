@@ -0,0 +1,187 @@
+package api
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"net/http"
+
+	"github.com/Black-tag/productAPI/internal/database"
+	"github.com/Black-tag/productAPI/internal/logger"
+	"github.com/Black-tag/productAPI/internal/models"
+	"github.com/Black-tag/productAPI/internal/permissions"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// productRelations is what a product response carries besides the products
+// row itself.
+type productRelations struct {
+	Categories []models.CategoryRef
+	Tags       []string
+	Variants   []models.VariantResponse
+	Images     []models.ProductImage
+	OnHand     int64
+}
+
+// loadProductRelations returns the categories, tags, variants, images and
+// stock of each product, with empty lists for products that have none.
+func (cfg *APIConfig) loadProductRelations(ctx context.Context, productIDs []uuid.UUID) (map[uuid.UUID]*productRelations, error) {
+	relations := make(map[uuid.UUID]*productRelations, len(productIDs))
+	for _, id := range productIDs {
+		relations[id] = &productRelations{
+			Categories: []models.CategoryRef{},
+			Tags:       []string{},
+			Variants:   []models.VariantResponse{},
+			Images:     []models.ProductImage{},
+		}
+	}
+	if len(productIDs) == 0 {
+		return relations, nil
+	}
+
+	categories, err := cfg.DB.ListCategoriesForProducts(ctx, productIDs)
+	if err != nil {
+		return nil, err
+	}
+	for _, row := range categories {
+		rel := relations[row.ProductID]
+		rel.Categories = append(rel.Categories, models.CategoryRef{
+			ID:   row.ID,
+			Name: row.Name,
+			Path: row.Path,
+		})
+	}
+
+	tags, err := cfg.DB.ListTagsForProducts(ctx, productIDs)
+	if err != nil {
+		return nil, err
+	}
+	for _, row := range tags {
+		rel := relations[row.ProductID]
+		rel.Tags = append(rel.Tags, row.Name)
+	}
+
+	variants, err := cfg.DB.ListVariantsForProducts(ctx, productIDs)
+	if err != nil {
+		return nil, err
+	}
+	for _, row := range variants {
+		rel := relations[row.ProductID]
+		rel.Variants = append(rel.Variants, models.VariantResponse{
+			ID:            row.ID,
+			SKU:           row.Sku,
+			Options:       variantOptions(row.Options),
+			Price:         priceFromDB(row.Price, row.Currency),
+			PriceOverride: row.PriceOverride,
+			OnHand:        row.OnHand,
+			CreatedAt:     row.CreatedAt,
+			UpdatedAt:     row.UpdatedAt,
+		})
+	}
+
+	images, err := cfg.DB.ListImagesForProducts(ctx, productIDs)
+	if err != nil {
+		return nil, err
+	}
+	for _, image := range images {
+		rel := relations[image.ProductID]
+		rel.Images = append(rel.Images, cfg.productImage(image))
+	}
+
+	stock, err := cfg.DB.SumStockForProducts(ctx, productIDs)
+	if err != nil {
+		return nil, err
+	}
+	for _, row := range stock {
+		relations[row.ProductID].OnHand = row.OnHand
+	}
+	return relations, nil
+}
+
+// attachRelations fills in the categories, tags, variants, images and stock
+// of product responses.
+func (cfg *APIConfig) attachRelations(ctx context.Context, products []*models.ProductResponse) error {
+	ids := make([]uuid.UUID, 0, len(products))
+	for _, product := range products {
+		ids = append(ids, product.ID)
+	}
+	relations, err := cfg.loadProductRelations(ctx, ids)
+	if err != nil {
+		return err
+	}
+	for _, product := range products {
+		relations[product.ID].attach(product)
+	}
+	return nil
+}
+
+func (rel *productRelations) attach(product *models.ProductResponse) {
+	product.Categories = rel.Categories
+	product.Tags = rel.Tags
+	product.Variants = rel.Variants
+	product.Images = rel.Images
+	product.OnHand = rel.OnHand
+}
+
+// withTouchedProduct runs write in a transaction that first bumps the
+// version of the product, for writes to what is nested in product
+// responses. The product ETag changes with them and concurrent writes to
+// the same product are serialized.
+func withTouchedProduct[T any](ctx context.Context, cfg *APIConfig, productID uuid.UUID, write func(*database.Queries) (T, error)) (T, database.Product, error) {
+	var zero T
+	tx, err := cfg.DBConn.BeginTx(ctx, nil)
+	if err != nil {
+		return zero, database.Product{}, err
+	}
+	defer tx.Rollback()
+	qtx := cfg.txQueries(tx)
+
+	product, err := qtx.TouchProduct(ctx, productID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return zero, database.Product{}, errProductNotFound
+	}
+	if err != nil {
+		return zero, database.Product{}, err
+	}
+	result, err := write(qtx)
+	if err != nil {
+		return zero, database.Product{}, err
+	}
+	if err := tx.Commit(); err != nil {
+		return zero, database.Product{}, err
+	}
+	return result, product, nil
+}
+
+// authorizeProductEdit applies the ownership rule of product updates to
+// writes of what is nested in the product in the path.
+func (cfg *APIConfig) authorizeProductEdit(w http.ResponseWriter, r *http.Request) (uuid.UUID, bool) {
+	userID, ok := r.Context().Value("userID").(uuid.UUID)
+	if !ok {
+		http.Error(w, "userID not in context", http.StatusUnauthorized)
+		return uuid.Nil, false
+	}
+	productID, err := uuid.Parse(r.PathValue("productID"))
+	if err != nil {
+		http.Error(w, "Invalid product id", http.StatusBadRequest)
+		return uuid.Nil, false
+	}
+
+	product, err := cfg.DB.GetProductByID(r.Context(), productID)
+	if errors.Is(err, sql.ErrNoRows) {
+		http.Error(w, "product not found", http.StatusNotFound)
+		return uuid.Nil, false
+	}
+	if err != nil {
+		logger.Log.Error("Failed to fetch product by ID", zap.String("productID", productID.String()), zap.Error(err))
+		http.Error(w, "databse operation failed", http.StatusInternalServerError)
+		return uuid.Nil, false
+	}
+	perms := permissions.FromContext(r.Context())
+	if !perms.CanModify(userID, product.PostedBy, permissions.ProductUpdateOwn, permissions.ProductUpdateAny) {
+		http.Error(w, "forbidden to edit product", http.StatusForbidden)
+		return uuid.Nil, false
+	}
+	return productID, true
+}
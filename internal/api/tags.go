@@ -0,0 +1,123 @@
+package api
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/Black-tag/productAPI/internal/database"
+	"github.com/Black-tag/productAPI/internal/logger"
+	"github.com/Black-tag/productAPI/internal/models"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+const (
+	maxTagLength   = 50
+	maxProductTags = 20
+)
+
+var errInvalidTag = errors.New("invalid tag")
+
+// @Summary List tags
+// @Description anyone can list the tags in use on products with how many products carry each, most used first
+// @Tags tags
+// @Produce json
+// @Param prefix query string false "only tags starting with this text"
+// @Param limit query int false "page size (default 20, max 100)"
+// @Param offset query int false "number of tags to skip"
+// @Success 200 {array} models.TagResponse
+// @Failure 400 {object} string "Bad Request - Invalid input"
+// @Failure 500 {object} string "Internal Server Error"
+// @Router /api/v1/tags [get]
+func (cfg *APIConfig) ListTagsHandler(w http.ResponseWriter, r *http.Request) {
+	logger.Log.Info("entered list tags handler")
+
+	limit, offset, err := parsePagination(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	params := database.ListTagsParams{PageSize: limit, PageOffset: offset}
+	if v := normalizeTag(r.URL.Query().Get("prefix")); v != "" {
+		params.Prefix = sql.NullString{String: escapeLike(v), Valid: true}
+	}
+
+	rows, err := cfg.DB.ListTags(r.Context(), params)
+	if err != nil {
+		logger.Log.Error("failed to list tags", zap.Error(err))
+		http.Error(w, "databse operation failed", http.StatusInternalServerError)
+		return
+	}
+
+	respPayload := make([]models.TagResponse, 0, len(rows))
+	for _, row := range rows {
+		respPayload = append(respPayload, models.TagResponse{
+			Name:         row.Name,
+			ProductCount: row.ProductCount,
+		})
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(respPayload); err != nil {
+		http.Error(w, "failed to encode tags", http.StatusInternalServerError)
+		return
+	}
+}
+
+// normalizeTag lowercases a tag and collapses its whitespace, so "Summer
+// Sale " and "summer  sale" are the same tag.
+func normalizeTag(tag string) string {
+	return strings.Join(strings.Fields(strings.ToLower(tag)), " ")
+}
+
+// normalizeTags normalizes and deduplicates the tags of a request, keeping
+// their order.
+func normalizeTags(tags []string) ([]string, error) {
+	if tags == nil {
+		return nil, nil
+	}
+	if len(tags) > maxProductTags {
+		return nil, fmt.Errorf("%w: a product can have at most %d tags", errInvalidTag, maxProductTags)
+	}
+	seen := make(map[string]bool, len(tags))
+	normalized := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		tag = normalizeTag(tag)
+		if tag == "" {
+			return nil, fmt.Errorf("%w: tags must not be empty", errInvalidTag)
+		}
+		if utf8.RuneCountInString(tag) > maxTagLength {
+			return nil, fmt.Errorf("%w: tags must be at most %d characters", errInvalidTag, maxTagLength)
+		}
+		if !seen[tag] {
+			seen[tag] = true
+			normalized = append(normalized, tag)
+		}
+	}
+	return normalized, nil
+}
+
+// setProductTags replaces the tags of a product, creating tags that do not
+// exist yet. It is meant to run inside the transaction that writes the
+// product.
+func setProductTags(ctx context.Context, q *database.Queries, productID uuid.UUID, tags []string) error {
+	if err := q.ClearProductTags(ctx, productID); err != nil {
+		return err
+	}
+	if len(tags) == 0 {
+		return nil
+	}
+	if err := q.EnsureTags(ctx, tags); err != nil {
+		return err
+	}
+	return q.AddProductTags(ctx, database.AddProductTagsParams{
+		ProductID: productID,
+		Names:     tags,
+	})
+}
@@ -0,0 +1,239 @@
+package api
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/Black-tag/productAPI/internal/database"
+	"github.com/Black-tag/productAPI/internal/logger"
+	"github.com/Black-tag/productAPI/internal/models"
+	"github.com/Black-tag/productAPI/internal/permissions"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// @Summary List deleted products
+// @Description users see their own deleted products, roles with product:delete:any see every deleted product. Newest deletions first
+// @Tags products
+// @Produce json
+// @Param limit query int false "page size (default 20, max 100)"
+// @Param offset query int false "number of products to skip"
+// @Success 200 {object} models.TrashListResponse
+// @Failure 400 {object} string "Bad Request - Invalid input"
+// @Failure 401 {object} string "Unauthorized - Missing/invalid credentials"
+// @Failure 500 {object} string "Internal Server Error"
+// @Router /api/v1/product/trash [get]
+// @Security BearerAuth
+func (cfg *APIConfig) ListTrashHandler(w http.ResponseWriter, r *http.Request) {
+	logger.Log.Info("entered list trash handler")
+
+	userID, ok := r.Context().Value("userID").(uuid.UUID)
+	if !ok {
+		http.Error(w, "userID not in context", http.StatusUnauthorized)
+		return
+	}
+	limit, offset, err := parsePagination(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	params := database.ListTrashedProductsParams{PageSize: limit, PageOffset: offset}
+	if !permissions.FromContext(r.Context()).Has(permissions.ProductDeleteAny) {
+		params.PostedBy = uuid.NullUUID{UUID: userID, Valid: true}
+	}
+	products, err := cfg.DB.ListTrashedProducts(r.Context(), params)
+	if err != nil {
+		logger.Log.Error("failed to list trashed products", zap.Error(err))
+		http.Error(w, "databse operation failed", http.StatusInternalServerError)
+		return
+	}
+
+	respPayload := models.TrashListResponse{
+		Products: make([]models.TrashedProductResponse, 0, len(products)),
+		Limit:    limit,
+		Offset:   offset,
+	}
+	for _, product := range products {
+		item := models.TrashedProductResponse{
+			ProductResponse: productResponse(product),
+			DeletedAt:       product.DeletedAt.Time,
+		}
+		if product.DeletedBy.Valid {
+			item.DeletedBy = &product.DeletedBy.UUID
+		}
+		respPayload.Products = append(respPayload.Products, item)
+	}
+	items := make([]*models.ProductResponse, 0, len(respPayload.Products))
+	for i := range respPayload.Products {
+		items = append(items, &respPayload.Products[i].ProductResponse)
+	}
+	if err := cfg.attachRelations(r.Context(), items); err != nil {
+		logger.Log.Error("failed to load product categories and tags", zap.Error(err))
+		http.Error(w, "databse operation failed", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(respPayload); err != nil {
+		http.Error(w, "failed to encode products", http.StatusInternalServerError)
+		return
+	}
+}
+
+// @Summary Restore a deleted product
+// @Description whoever may delete a product may restore it from the trash, until it is purged
+// @Tags products
+// @Produce json
+// @Param productID path string true "productID"
+// @Param If-Match header string false "ETag of the deleted product"
+// @Success 200 {object} models.ProductResponse
+// @Failure 400 {object} string "Bad Request - Invalid input"
+// @Failure 401 {object} string "Unauthorized - Missing/invalid credentials"
+// @Failure 403 {object} string "Forbidden - Insufficient permissions"
+// @Failure 404 {object} string "Not Found - product is not in the trash"
+// @Failure 412 {object} string "Precondition Failed - product has been modified"
+// @Failure 428 {object} string "Precondition Required - If-Match header missing"
+// @Failure 500 {object} string "Internal Server Error"
+// @Router /api/v1/product/{productID}/restore [post]
+// @Security BearerAuth
+func (cfg *APIConfig) RestoreProductHandler(w http.ResponseWriter, r *http.Request) {
+	logger.Log.Info("entered restore product handler")
+
+	userID, ok := r.Context().Value("userID").(uuid.UUID)
+	if !ok {
+		http.Error(w, "userID not in context", http.StatusUnauthorized)
+		return
+	}
+	productID, err := uuid.Parse(r.PathValue("productID"))
+	if err != nil {
+		http.Error(w, "Invalid product id", http.StatusBadRequest)
+		return
+	}
+
+	product, err := cfg.DB.GetTrashedProductByID(r.Context(), productID)
+	if errors.Is(err, sql.ErrNoRows) {
+		http.Error(w, "product not found in trash", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		logger.Log.Error("Failed to fetch trashed product", zap.String("productID", productID.String()), zap.Error(err))
+		http.Error(w, "databse operation failed", http.StatusInternalServerError)
+		return
+	}
+	perms := permissions.FromContext(r.Context())
+	if !perms.CanModify(userID, product.PostedBy, permissions.ProductDeleteOwn, permissions.ProductDeleteAny) {
+		http.Error(w, "forbidden to restore product", http.StatusForbidden)
+		return
+	}
+	expectedVersion, ok := cfg.checkIfMatch(w, r, product)
+	if !ok {
+		return
+	}
+
+	restored, err := cfg.DB.RestoreProduct(r.Context(), database.RestoreProductParams{
+		ID:              productID,
+		ExpectedVersion: expectedVersion,
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		writeLostWrite(w, expectedVersion)
+		return
+	}
+	if err != nil {
+		logger.Log.Error("Failed to restore product", zap.String("productID", productID.String()), zap.Error(err))
+		http.Error(w, "databse operation failed", http.StatusInternalServerError)
+		return
+	}
+	logger.Log.Info("product restored", zap.String("productID", productID.String()), zap.String("userID", userID.String()))
+
+	respPayload := productResponse(restored)
+	if err := cfg.attachRelations(r.Context(), []*models.ProductResponse{&respPayload}); err != nil {
+		logger.Log.Error("failed to load product categories and tags", zap.Error(err))
+		http.Error(w, "databse operation failed", http.StatusInternalServerError)
+		return
+	}
+	setValidators(w, restored)
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(respPayload); err != nil {
+		http.Error(w, "failed to encode product", http.StatusInternalServerError)
+		return
+	}
+}
+
+// @Summary Purge a deleted product
+// @Description admin can permanently remove a product from the trash together with its price history and image files. Products that are not in the trash cannot be purged
+// @Tags admin
+// @Param productID path string true "productID"
+// @Success 204 {string} string "No content"
+// @Failure 400 {object} string "Bad Request - Invalid input"
+// @Failure 401 {object} string "Unauthorized - Missing/invalid credentials"
+// @Failure 403 {object} string "Forbidden - Insufficient permissions"
+// @Failure 404 {object} string "Not Found - product is not in the trash"
+// @Failure 500 {object} string "Internal Server Error"
+// @Router /api/v1/admin/products/{productID} [delete]
+// @Security BearerAuth
+func (cfg *APIConfig) PurgeProductHandler(w http.ResponseWriter, r *http.Request) {
+	logger.Log.Info("entered purge product handler")
+
+	productID, err := uuid.Parse(r.PathValue("productID"))
+	if err != nil {
+		http.Error(w, "Invalid product id", http.StatusBadRequest)
+		return
+	}
+
+	purged, err := cfg.DB.PurgeProduct(r.Context(), productID)
+	if err != nil {
+		logger.Log.Error("failed to purge product", zap.String("productID", productID.String()), zap.Error(err))
+		http.Error(w, "databse deletion failed", http.StatusInternalServerError)
+		return
+	}
+	if len(purged) == 0 {
+		http.Error(w, "product not found in trash", http.StatusNotFound)
+		return
+	}
+	logger.Log.Info("product purged", zap.String("productID", productID.String()))
+	for _, row := range purged {
+		cfg.deleteImageFiles(r.Context(), row.StorageKey.String, row.ThumbnailKey.String)
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// RunTrashPurge removes products that have been in the trash for longer than
+// TrashRetention, once at start and then every interval, until ctx is done.
+func (cfg *APIConfig) RunTrashPurge(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		cfg.purgeExpiredTrash(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (cfg *APIConfig) purgeExpiredTrash(ctx context.Context) {
+	cutoff := time.Now().Add(-cfg.TrashRetention)
+	purged, err := cfg.DB.PurgeTrashedProducts(ctx, cutoff)
+	if err != nil && ctx.Err() != nil {
+		// shutting down, the next start purges them
+		return
+	}
+	if err != nil {
+		logger.Log.Error("failed to purge trashed products", zap.Time("trashedBefore", cutoff), zap.Error(err))
+		return
+	}
+	products := make(map[uuid.UUID]bool)
+	for _, row := range purged {
+		products[row.ID] = true
+		cfg.deleteImageFiles(ctx, row.StorageKey.String, row.ThumbnailKey.String)
+	}
+	if len(products) > 0 {
+		logger.Log.Info("purged trashed products", zap.Int("count", len(products)), zap.Time("trashedBefore", cutoff))
+	}
+}
@@ -3,14 +3,17 @@ package api
 import (
 	"database/sql"
 	"encoding/json"
+	"errors"
 	"time"
 
 	"net/http"
 
 	"github.com/Black-tag/productAPI/internal/database"
 	"github.com/Black-tag/productAPI/internal/logger"
+	"github.com/Black-tag/productAPI/internal/metrics"
 	"github.com/Black-tag/productAPI/internal/models"
 	"github.com/Black-tag/productAPI/internal/utils"
+	"github.com/google/uuid"
 
 	"go.uber.org/zap"
 )
@@ -23,6 +26,7 @@ import (
 // @Param request body models.UserRequest true "User creation data"
 // @Success 201 {object} database.User
 // @Failure 400 {object} string "Bad Request - Invalid input"
+// @Failure 409 {object} string "Conflict - email already registered"
 // @Failure 500 {object} string "Internal Server Error"
 // @Router /api/v1/users [post]
 // @Security BearerAuth
@@ -43,7 +47,7 @@ func (cfg *APIConfig) CreateUserHandler(w http.ResponseWriter, r *http.Request)
 		zap.String("email_in_request", req.Email),
 	)
 
-	hashdepassword, err := utils.HashPassword(req.Password)
+	hashdepassword, err := utils.HashPassword(req.Password, cfg.BcryptCost)
 	if err != nil {
 		http.Error(w, "cannot hash password", http.StatusInternalServerError)
 		return
@@ -53,6 +57,10 @@ func (cfg *APIConfig) CreateUserHandler(w http.ResponseWriter, r *http.Request)
 		Email:          req.Email,
 		Hashedpassword: hashdepassword,
 	})
+	if isPQError(err, pqUniqueViolation) {
+		http.Error(w, "a user with this email already exists", http.StatusConflict)
+		return
+	}
 	if err != nil {
 		logger.Log.Error("cannot create user in databse")
 		http.Error(w, "databse operation failed", http.StatusInternalServerError)
@@ -94,6 +102,7 @@ func (cfg *APIConfig) CreateUserHandler(w http.ResponseWriter, r *http.Request)
 // @Success 200 {object} models.LoginResponse
 // @Failure 400 {object} string "Bad Request - Invalid input"
 // @Failure 401 {object} string "Invalid credentials"
+// @Failure 403 {object} string "Account disabled"
 // @Failure 500 {object} string "Internal Server Error"
 // @Router /api/v1/login [post]
 // @Security BearerAuth
@@ -106,14 +115,21 @@ func (cfg *APIConfig) UserLoginHandler(w http.ResponseWriter, r *http.Request) {
 
 	user, err := cfg.DB.GetUserByEmail(r.Context(), req.Email)
 	if err != nil {
+		metrics.LoginFailures.WithLabelValues("unknown_user").Inc()
 		http.Error(w, "user does not exists", http.StatusNotFound)
 		return
 	}
 	if err := utils.CheckPasswordAndHash(req.Password, user.Hashedpassword); err != nil {
+		metrics.LoginFailures.WithLabelValues("bad_password").Inc()
 		http.Error(w, "invalid credentials", http.StatusUnauthorized)
 		return
 	}
-	token, err := utils.MakeJWT(user.ID, cfg.SECRET, time.Hour)
+	if user.DisabledAt.Valid {
+		metrics.LoginFailures.WithLabelValues("disabled").Inc()
+		http.Error(w, "account is disabled", http.StatusForbidden)
+		return
+	}
+	token, err := utils.MakeJWT(user.ID, cfg.SECRET, cfg.AccessTokenTTL)
 	if err != nil {
 		http.Error(w, "cannot create jwt", http.StatusInternalServerError)
 		return
@@ -123,7 +139,7 @@ func (cfg *APIConfig) UserLoginHandler(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, "cannot create refresh token", http.StatusInternalServerError)
 		return
 	}
-	refreshExpiresAt := time.Now().Add(30 * 24 * time.Hour)
+	refreshExpiresAt := time.Now().Add(cfg.RefreshTokenTTL)
 
 	err = cfg.DB.CreateRefreshToken(r.Context(), database.CreateRefreshTokenParams{
 		Token:     refreshToken,
@@ -132,6 +148,7 @@ func (cfg *APIConfig) UserLoginHandler(w http.ResponseWriter, r *http.Request) {
 		UpdatedAt: time.Now(),
 		ExpiresAt: refreshExpiresAt,
 		RevokedAt: sql.NullTime{},
+		FamilyID:  uuid.New(),
 	})
 	if err != nil {
 		http.Error(w, "cannot create refresh toke", http.StatusInternalServerError)
@@ -151,7 +168,198 @@ func (cfg *APIConfig) UserLoginHandler(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, "cannot marshal json", http.StatusInternalServerError)
 		return
 	}
+	metrics.Logins.Inc()
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	w.Write(resp)
+}
+
+// @Summary Exchange a refresh token
+// @Description Exchanges a valid refresh token for a new access token. The presented refresh token is rotated; reusing an already rotated token revokes every token in its family
+// @Tags users
+// @Accept json
+// @Produce json
+// @Param request body models.RefreshRequest true "refresh token"
+// @Success 200 {object} models.RefreshResponse
+// @Failure 400 {object} string "Bad Request - Invalid input"
+// @Failure 401 {object} string "Invalid, expired or revoked refresh token"
+// @Failure 500 {object} string "Internal Server Error"
+// @Router /api/v1/refresh [post]
+func (cfg *APIConfig) RefreshTokenHandler(w http.ResponseWriter, r *http.Request) {
+	logger.Log.Info("entered refresh token handler")
+
+	var req models.RefreshRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.RefreshToken == "" {
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	stored, err := cfg.DB.GetRefreshToken(r.Context(), req.RefreshToken)
+	if errors.Is(err, sql.ErrNoRows) {
+		http.Error(w, "invalid refresh token", http.StatusUnauthorized)
+		return
+	}
+	if err != nil {
+		logger.Log.Error("failed to fetch refresh token", zap.Error(err))
+		http.Error(w, "databse operation failed", http.StatusInternalServerError)
+		return
+	}
+
+	if stored.RevokedAt.Valid {
+		if stored.ReplacedBy.Valid {
+			cfg.revokeRefreshTokenFamily(r, stored)
+		}
+		http.Error(w, "refresh token has been revoked", http.StatusUnauthorized)
+		return
+	}
+	if time.Now().After(stored.ExpiresAt) {
+		http.Error(w, "refresh token has expired", http.StatusUnauthorized)
+		return
+	}
+
+	newRefreshToken, err := utils.MakeRefreshToken()
+	if err != nil {
+		http.Error(w, "cannot create refresh token", http.StatusInternalServerError)
+		return
+	}
+
+	tx, err := cfg.DBConn.BeginTx(r.Context(), nil)
+	if err != nil {
+		logger.Log.Error("failed to begin transaction", zap.Error(err))
+		http.Error(w, "databse operation failed", http.StatusInternalServerError)
+		return
+	}
+	defer tx.Rollback()
+	qtx := cfg.txQueries(tx)
+
+	rotated, err := qtx.RotateRefreshToken(r.Context(), database.RotateRefreshTokenParams{
+		Token:      stored.Token,
+		ReplacedBy: sql.NullString{String: newRefreshToken, Valid: true},
+	})
+	if err != nil {
+		logger.Log.Error("failed to rotate refresh token", zap.Error(err))
+		http.Error(w, "databse operation failed", http.StatusInternalServerError)
+		return
+	}
+	if rotated == 0 {
+		// another request rotated this token between our read and update
+		tx.Rollback()
+		cfg.revokeRefreshTokenFamily(r, stored)
+		http.Error(w, "refresh token has been revoked", http.StatusUnauthorized)
+		return
+	}
+
+	now := time.Now()
+	err = qtx.CreateRefreshToken(r.Context(), database.CreateRefreshTokenParams{
+		Token:     newRefreshToken,
+		UserID:    stored.UserID,
+		CreatedAt: now,
+		UpdatedAt: now,
+		ExpiresAt: now.Add(cfg.RefreshTokenTTL),
+		RevokedAt: sql.NullTime{},
+		FamilyID:  stored.FamilyID,
+	})
+	if err != nil {
+		logger.Log.Error("failed to store rotated refresh token", zap.Error(err))
+		http.Error(w, "cannot create refresh token", http.StatusInternalServerError)
+		return
+	}
+	if err := tx.Commit(); err != nil {
+		logger.Log.Error("failed to commit refresh token rotation", zap.Error(err))
+		http.Error(w, "databse operation failed", http.StatusInternalServerError)
+		return
+	}
+
+	token, err := utils.MakeJWT(stored.UserID, cfg.SECRET, cfg.AccessTokenTTL)
+	if err != nil {
+		http.Error(w, "cannot create jwt", http.StatusInternalServerError)
+		return
+	}
+
+	respPayload := models.RefreshResponse{
+		Token:        token,
+		RefreshToken: newRefreshToken,
+	}
+	resp, err := json.Marshal(respPayload)
+	if err != nil {
+		http.Error(w, "cannot marshal json", http.StatusInternalServerError)
+		return
+	}
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusOK)
 	w.Write(resp)
 }
+
+// revokeRefreshTokenFamily is called when a rotated refresh token is presented
+// again, which means it has leaked; every token issued from the same login is
+// revoked so neither party can keep using the session.
+func (cfg *APIConfig) revokeRefreshTokenFamily(r *http.Request, stored database.RefreshToken) {
+	logger.Log.Warn("refresh token reuse detected, revoking token family",
+		zap.String("userID", stored.UserID.String()),
+		zap.String("familyID", stored.FamilyID.String()),
+	)
+	if err := cfg.DB.RevokeRefreshTokenFamily(r.Context(), stored.FamilyID); err != nil {
+		logger.Log.Error("failed to revoke refresh token family", zap.Error(err))
+	}
+}
+
+// @Summary Logout
+// @Description Revokes the presented refresh token. Revoking an unknown or already revoked token is not an error
+// @Tags users
+// @Accept json
+// @Produce json
+// @Param request body models.RefreshRequest true "refresh token"
+// @Success 204 {string} string "No content"
+// @Failure 400 {object} string "Bad Request - Invalid input"
+// @Failure 500 {object} string "Internal Server Error"
+// @Router /api/v1/logout [post]
+func (cfg *APIConfig) LogoutHandler(w http.ResponseWriter, r *http.Request) {
+	logger.Log.Info("entered logout handler")
+
+	var req models.RefreshRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.RefreshToken == "" {
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	revoked, err := cfg.DB.RevokeRefreshToken(r.Context(), req.RefreshToken)
+	if err != nil {
+		logger.Log.Error("failed to revoke refresh token", zap.Error(err))
+		http.Error(w, "databse operation failed", http.StatusInternalServerError)
+		return
+	}
+	logger.Log.Info("refresh token revoked", zap.Int64("revoked", revoked))
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// @Summary Logout from every session
+// @Description Revokes every refresh token issued to the authenticated user
+// @Tags users
+// @Produce json
+// @Success 204 {string} string "No content"
+// @Failure 401 {object} string "Unauthorized - Missing/invalid credentials"
+// @Failure 500 {object} string "Internal Server Error"
+// @Router /api/v1/logout-all [post]
+// @Security BearerAuth
+func (cfg *APIConfig) LogoutAllHandler(w http.ResponseWriter, r *http.Request) {
+	logger.Log.Info("entered logout all handler")
+
+	userIDValue := r.Context().Value("userID")
+	userID, ok := userIDValue.(uuid.UUID)
+	if !ok {
+		http.Error(w, "userID not in context", http.StatusUnauthorized)
+		return
+	}
+
+	revoked, err := cfg.DB.RevokeAllRefreshTokensForUser(r.Context(), userID)
+	if err != nil {
+		logger.Log.Error("failed to revoke refresh tokens", zap.String("userID", userID.String()), zap.Error(err))
+		http.Error(w, "databse operation failed", http.StatusInternalServerError)
+		return
+	}
+	logger.Log.Info("revoked all refresh tokens for user",
+		zap.String("userID", userID.String()),
+		zap.Int64("revoked", revoked),
+	)
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,351 @@
+package api
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"net/http"
+	"slices"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/Black-tag/productAPI/internal/database"
+	"github.com/Black-tag/productAPI/internal/logger"
+	"github.com/Black-tag/productAPI/internal/models"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+const (
+	maxVariantOptions      = 5
+	maxVariantOptionLength = 50
+)
+
+var (
+	errVariantNotFound = errors.New("variant not found")
+	errInvalidVariant  = errors.New("invalid variant")
+	errVariantHasStock = errors.New("variant still has stock on hand")
+	errProductNotFound = errors.New("product not found")
+)
+
+// @Summary Add a variant to a product
+// @Description the owner of a product, or roles with product:update:any, can add a variant with its own SKU, option values such as size and colour, and optionally its own price. All variants of a product have the same option names
+// @Tags products
+// @Accept json
+// @Produce json
+// @Param productID path string true "productID"
+// @Param request body models.VariantRequest true "variant"
+// @Success 201 {object} models.VariantResponse
+// @Failure 400 {object} string "Bad Request - Invalid input"
+// @Failure 401 {object} string "Unauthorized - Missing/invalid credentials"
+// @Failure 403 {object} string "Forbidden - Insufficient permissions"
+// @Failure 404 {object} string "Not Found - Resource doesn't exist"
+// @Failure 409 {object} string "Conflict - SKU already used by a product or variant, or options already used"
+// @Failure 500 {object} string "Internal Server Error"
+// @Router /api/v1/product/{productID}/variants [post]
+// @Security BearerAuth
+func (cfg *APIConfig) CreateVariantHandler(w http.ResponseWriter, r *http.Request) {
+	logger.Log.Info("entered create variant handler")
+
+	productID, ok := cfg.authorizeProductEdit(w, r)
+	if !ok {
+		return
+	}
+	req, params, ok := decodeVariantRequest(w, r)
+	if !ok {
+		return
+	}
+
+	variant, product, err := withTouchedProduct(r.Context(), cfg, productID, func(q *database.Queries) (database.ProductVariant, error) {
+		if err := checkVariantOptionNames(r.Context(), q, productID, uuid.Nil, req.Options); err != nil {
+			return database.ProductVariant{}, err
+		}
+		return q.CreateProductVariant(r.Context(), database.CreateProductVariantParams{
+			ProductID: productID,
+			Sku:       req.SKU,
+			Options:   params.options,
+			Price:     params.price,
+			Currency:  params.currency,
+		})
+	})
+	if err != nil {
+		writeVariantError(w, err)
+		return
+	}
+	logger.Log.Info("variant created", zap.String("productID", productID.String()), zap.String("variantID", variant.ID.String()))
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	if err := json.NewEncoder(w).Encode(variantResponse(variant, product, 0)); err != nil {
+		http.Error(w, "failed to encode variant", http.StatusInternalServerError)
+		return
+	}
+}
+
+// @Summary Update a variant
+// @Description the owner of a product, or roles with product:update:any, can replace the SKU, options and price of one of its variants. Leaving out the price makes the variant use the product price
+// @Tags products
+// @Accept json
+// @Produce json
+// @Param productID path string true "productID"
+// @Param variantID path string true "variantID"
+// @Param request body models.VariantRequest true "variant"
+// @Success 200 {object} models.VariantResponse
+// @Failure 400 {object} string "Bad Request - Invalid input"
+// @Failure 401 {object} string "Unauthorized - Missing/invalid credentials"
+// @Failure 403 {object} string "Forbidden - Insufficient permissions"
+// @Failure 404 {object} string "Not Found - Resource doesn't exist"
+// @Failure 409 {object} string "Conflict - SKU already used by a product or variant, or options already used"
+// @Failure 500 {object} string "Internal Server Error"
+// @Router /api/v1/product/{productID}/variants/{variantID} [put]
+// @Security BearerAuth
+func (cfg *APIConfig) UpdateVariantHandler(w http.ResponseWriter, r *http.Request) {
+	logger.Log.Info("entered update variant handler")
+
+	variantID, err := uuid.Parse(r.PathValue("variantID"))
+	if err != nil {
+		http.Error(w, "Invalid variant id", http.StatusBadRequest)
+		return
+	}
+	productID, ok := cfg.authorizeProductEdit(w, r)
+	if !ok {
+		return
+	}
+	req, params, ok := decodeVariantRequest(w, r)
+	if !ok {
+		return
+	}
+
+	variant, product, err := withTouchedProduct(r.Context(), cfg, productID, func(q *database.Queries) (database.ProductVariant, error) {
+		if err := checkVariantOptionNames(r.Context(), q, productID, variantID, req.Options); err != nil {
+			return database.ProductVariant{}, err
+		}
+		variant, err := q.UpdateProductVariant(r.Context(), database.UpdateProductVariantParams{
+			ID:        variantID,
+			ProductID: productID,
+			Sku:       req.SKU,
+			Options:   params.options,
+			Price:     params.price,
+			Currency:  params.currency,
+		})
+		if errors.Is(err, sql.ErrNoRows) {
+			return database.ProductVariant{}, errVariantNotFound
+		}
+		return variant, err
+	})
+	if err != nil {
+		writeVariantError(w, err)
+		return
+	}
+	onHand, err := cfg.DB.GetVariantOnHand(r.Context(), uuid.NullUUID{UUID: variant.ID, Valid: true})
+	if err != nil {
+		logger.Log.Error("failed to fetch variant stock", zap.String("variantID", variant.ID.String()), zap.Error(err))
+		http.Error(w, "databse operation failed", http.StatusInternalServerError)
+		return
+	}
+	logger.Log.Info("variant updated", zap.String("productID", productID.String()), zap.String("variantID", variant.ID.String()))
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(variantResponse(variant, product, onHand)); err != nil {
+		http.Error(w, "failed to encode variant", http.StatusInternalServerError)
+		return
+	}
+}
+
+// @Summary Delete a variant
+// @Description the owner of a product, or roles with product:update:any, can delete one of its variants once none of it is on hand. Its stock ledger goes with it
+// @Tags products
+// @Param productID path string true "productID"
+// @Param variantID path string true "variantID"
+// @Success 204 {string} string "No content"
+// @Failure 400 {object} string "Bad Request - Invalid input"
+// @Failure 401 {object} string "Unauthorized - Missing/invalid credentials"
+// @Failure 403 {object} string "Forbidden - Insufficient permissions"
+// @Failure 404 {object} string "Not Found - Resource doesn't exist"
+// @Failure 409 {object} string "Conflict - variant still has stock on hand"
+// @Failure 500 {object} string "Internal Server Error"
+// @Router /api/v1/product/{productID}/variants/{variantID} [delete]
+// @Security BearerAuth
+func (cfg *APIConfig) DeleteVariantHandler(w http.ResponseWriter, r *http.Request) {
+	logger.Log.Info("entered delete variant handler")
+
+	variantID, err := uuid.Parse(r.PathValue("variantID"))
+	if err != nil {
+		http.Error(w, "Invalid variant id", http.StatusBadRequest)
+		return
+	}
+	productID, ok := cfg.authorizeProductEdit(w, r)
+	if !ok {
+		return
+	}
+
+	_, _, err = withTouchedProduct(r.Context(), cfg, productID, func(q *database.Queries) (database.ProductVariant, error) {
+		// waits for stock movements of the variant in flight, which hold
+		// a share lock on it, so the stock read below is final
+		_, err := q.LockProductVariant(r.Context(), database.LockProductVariantParams{
+			ID:        variantID,
+			ProductID: productID,
+		})
+		if errors.Is(err, sql.ErrNoRows) {
+			return database.ProductVariant{}, errVariantNotFound
+		}
+		if err != nil {
+			return database.ProductVariant{}, err
+		}
+		onHand, err := q.GetVariantOnHand(r.Context(), uuid.NullUUID{UUID: variantID, Valid: true})
+		if err != nil {
+			return database.ProductVariant{}, err
+		}
+		if onHand > 0 {
+			return database.ProductVariant{}, fmt.Errorf("%w: %d", errVariantHasStock, onHand)
+		}
+		_, err = q.DeleteProductVariant(r.Context(), database.DeleteProductVariantParams{
+			ID:        variantID,
+			ProductID: productID,
+		})
+		return database.ProductVariant{}, err
+	})
+	if err != nil {
+		writeVariantError(w, err)
+		return
+	}
+	logger.Log.Info("variant deleted", zap.String("productID", productID.String()), zap.String("variantID", variantID.String()))
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// checkVariantOptionNames checks options use the same option names as the
+// other variants of the product, excludeID being the variant written.
+func checkVariantOptionNames(ctx context.Context, q *database.Queries, productID, excludeID uuid.UUID, options map[string]string) error {
+	raw, err := q.GetOtherVariantOptions(ctx, database.GetOtherVariantOptionsParams{
+		ProductID: productID,
+		ExcludeID: excludeID,
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	want := slices.Sorted(maps.Keys(variantOptions(raw)))
+	if !slices.Equal(want, slices.Sorted(maps.Keys(options))) {
+		return fmt.Errorf("%w: variants of this product have the options %s", errInvalidVariant, strings.Join(want, ", "))
+	}
+	return nil
+}
+
+func writeVariantError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, errProductNotFound), errors.Is(err, errVariantNotFound):
+		http.Error(w, err.Error(), http.StatusNotFound)
+	case errors.Is(err, errInvalidVariant):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	case errors.Is(err, errVariantHasStock):
+		http.Error(w, err.Error(), http.StatusConflict)
+	case pqConstraint(err, pqUniqueViolation) == "product_variants_sku_key":
+		http.Error(w, "a variant with this sku already exists", http.StatusConflict)
+	case pqConstraint(err, pqUniqueViolation) == "skus_pkey":
+		http.Error(w, "sku already used by another product or variant", http.StatusConflict)
+	case pqConstraint(err, pqUniqueViolation) == "product_variants_options_key":
+		http.Error(w, "the product already has a variant with these options", http.StatusConflict)
+	default:
+		logger.Log.Error("failed to write variant", zap.Error(err))
+		http.Error(w, "databse operation failed", http.StatusInternalServerError)
+	}
+}
+
+// variantParams is a variant request in the form it is stored in.
+type variantParams struct {
+	options  json.RawMessage
+	price    sql.NullString
+	currency sql.NullString
+}
+
+func decodeVariantRequest(w http.ResponseWriter, r *http.Request) (models.VariantRequest, variantParams, bool) {
+	var req models.VariantRequest
+	var params variantParams
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return req, params, false
+	}
+	sku, err := models.NormalizeSKU(req.SKU)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return req, params, false
+	}
+	req.SKU = sku
+	req.Options, err = normalizeVariantOptions(req.Options)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return req, params, false
+	}
+	if req.Price != nil {
+		if err := validateProductPrice(*req.Price); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return req, params, false
+		}
+		params.price = sql.NullString{String: req.Price.Amount(), Valid: true}
+		params.currency = sql.NullString{String: req.Price.Currency, Valid: true}
+	}
+	params.options, err = json.Marshal(req.Options)
+	if err != nil {
+		http.Error(w, "invalid options", http.StatusBadRequest)
+		return req, params, false
+	}
+	return req, params, true
+}
+
+// normalizeVariantOptions lowercases option names and collapses whitespace
+// in names and values, so "Colour" and "colour " name the same option.
+func normalizeVariantOptions(options map[string]string) (map[string]string, error) {
+	if len(options) == 0 {
+		return nil, fmt.Errorf("%w: a variant needs at least one option", errInvalidVariant)
+	}
+	if len(options) > maxVariantOptions {
+		return nil, fmt.Errorf("%w: a variant can have at most %d options", errInvalidVariant, maxVariantOptions)
+	}
+	normalized := make(map[string]string, len(options))
+	for name, value := range options {
+		name = normalizeTag(name)
+		value = strings.Join(strings.Fields(value), " ")
+		if name == "" || value == "" {
+			return nil, fmt.Errorf("%w: option names and values must not be empty", errInvalidVariant)
+		}
+		if utf8.RuneCountInString(name) > maxVariantOptionLength || utf8.RuneCountInString(value) > maxVariantOptionLength {
+			return nil, fmt.Errorf("%w: option names and values must be at most %d characters", errInvalidVariant, maxVariantOptionLength)
+		}
+		if _, ok := normalized[name]; ok {
+			return nil, fmt.Errorf("%w: option %q given twice", errInvalidVariant, name)
+		}
+		normalized[name] = value
+	}
+	return normalized, nil
+}
+
+// variantOptions decodes the stored options of a variant.
+func variantOptions(raw json.RawMessage) map[string]string {
+	options := map[string]string{}
+	if err := json.Unmarshal(raw, &options); err != nil {
+		logger.Log.Error("cannot parse variant options from database", zap.ByteString("options", raw), zap.Error(err))
+	}
+	return options
+}
+
+func variantResponse(variant database.ProductVariant, product database.Product, onHand int64) models.VariantResponse {
+	resp := models.VariantResponse{
+		ID:        variant.ID,
+		SKU:       variant.Sku,
+		Options:   variantOptions(variant.Options),
+		Price:     priceFromDB(product.Price, product.Currency),
+		OnHand:    onHand,
+		CreatedAt: variant.CreatedAt,
+		UpdatedAt: variant.UpdatedAt,
+	}
+	if variant.Price.Valid {
+		resp.Price = priceFromDB(variant.Price.String, variant.Currency.String)
+		resp.PriceOverride = true
+	}
+	return resp
+}
@@ -0,0 +1,288 @@
+package api
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"regexp"
+	"strings"
+
+	"github.com/Black-tag/productAPI/internal/database"
+	"github.com/Black-tag/productAPI/internal/logger"
+	"github.com/Black-tag/productAPI/internal/models"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+var warehouseCodePattern = regexp.MustCompile(`^[A-Z0-9][A-Z0-9_-]{0,15}$`)
+
+var (
+	errWarehouseNotFound = errors.New("warehouse not found")
+	errDefaultWarehouse  = errors.New("the default warehouse cannot be unset or deleted, make another warehouse the default instead")
+)
+
+// @Summary List warehouses
+// @Description anyone can list the warehouses stock is kept in, ordered by code
+// @Tags inventory
+// @Produce json
+// @Success 200 {array} models.WarehouseResponse
+// @Failure 500 {object} string "Internal Server Error"
+// @Router /api/v1/warehouses [get]
+func (cfg *APIConfig) ListWarehousesHandler(w http.ResponseWriter, r *http.Request) {
+	logger.Log.Info("entered list warehouses handler")
+
+	warehouses, err := cfg.DB.ListWarehouses(r.Context())
+	if err != nil {
+		logger.Log.Error("failed to list warehouses", zap.Error(err))
+		http.Error(w, "databse operation failed", http.StatusInternalServerError)
+		return
+	}
+
+	respPayload := make([]models.WarehouseResponse, 0, len(warehouses))
+	for _, warehouse := range warehouses {
+		respPayload = append(respPayload, warehouseResponse(warehouse))
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(respPayload); err != nil {
+		http.Error(w, "failed to encode warehouses", http.StatusInternalServerError)
+		return
+	}
+}
+
+// @Summary Create a warehouse
+// @Description admin can add a warehouse. Codes are unique and uppercased. Creating it as the default makes it replace the current default warehouse
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param request body models.WarehouseRequest true "warehouse"
+// @Success 201 {object} models.WarehouseResponse
+// @Failure 400 {object} string "Bad Request - Invalid input"
+// @Failure 401 {object} string "Unauthorized - Missing/invalid credentials"
+// @Failure 403 {object} string "Forbidden - Insufficient permissions"
+// @Failure 409 {object} string "Conflict - code already used"
+// @Failure 500 {object} string "Internal Server Error"
+// @Router /api/v1/admin/warehouses [post]
+// @Security BearerAuth
+func (cfg *APIConfig) CreateWarehouseHandler(w http.ResponseWriter, r *http.Request) {
+	logger.Log.Info("entered create warehouse handler")
+
+	req, ok := decodeWarehouseRequest(w, r)
+	if !ok {
+		return
+	}
+
+	warehouse, err := cfg.withWarehouses(r.Context(), func(q *database.Queries) (database.Warehouse, error) {
+		if req.IsDefault {
+			if err := q.ClearDefaultWarehouse(r.Context(), uuid.Nil); err != nil {
+				return database.Warehouse{}, err
+			}
+		}
+		return q.CreateWarehouse(r.Context(), database.CreateWarehouseParams{
+			Code:      req.Code,
+			Name:      req.Name,
+			IsDefault: req.IsDefault,
+		})
+	})
+	if err != nil {
+		writeWarehouseError(w, err)
+		return
+	}
+	logger.Log.Info("warehouse created", zap.String("warehouseID", warehouse.ID.String()), zap.String("code", warehouse.Code))
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	if err := json.NewEncoder(w).Encode(warehouseResponse(warehouse)); err != nil {
+		http.Error(w, "failed to encode warehouse", http.StatusInternalServerError)
+		return
+	}
+}
+
+// @Summary Update a warehouse
+// @Description admin can rename a warehouse, change its code or make it the default. The default warehouse stays the default until another one is made the default
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param warehouseID path string true "warehouseID"
+// @Param request body models.WarehouseRequest true "warehouse"
+// @Success 200 {object} models.WarehouseResponse
+// @Failure 400 {object} string "Bad Request - Invalid input"
+// @Failure 401 {object} string "Unauthorized - Missing/invalid credentials"
+// @Failure 403 {object} string "Forbidden - Insufficient permissions"
+// @Failure 404 {object} string "Not Found - Resource doesn't exist"
+// @Failure 409 {object} string "Conflict - code already used, or unsetting the default warehouse"
+// @Failure 500 {object} string "Internal Server Error"
+// @Router /api/v1/admin/warehouses/{warehouseID} [put]
+// @Security BearerAuth
+func (cfg *APIConfig) UpdateWarehouseHandler(w http.ResponseWriter, r *http.Request) {
+	logger.Log.Info("entered update warehouse handler")
+
+	warehouseID, err := uuid.Parse(r.PathValue("warehouseID"))
+	if err != nil {
+		http.Error(w, "Invalid warehouse id", http.StatusBadRequest)
+		return
+	}
+	req, ok := decodeWarehouseRequest(w, r)
+	if !ok {
+		return
+	}
+
+	warehouse, err := cfg.withWarehouses(r.Context(), func(q *database.Queries) (database.Warehouse, error) {
+		current, err := q.GetWarehouseByID(r.Context(), warehouseID)
+		if errors.Is(err, sql.ErrNoRows) {
+			return database.Warehouse{}, errWarehouseNotFound
+		}
+		if err != nil {
+			return database.Warehouse{}, err
+		}
+		if current.IsDefault && !req.IsDefault {
+			return database.Warehouse{}, errDefaultWarehouse
+		}
+		if req.IsDefault && !current.IsDefault {
+			if err := q.ClearDefaultWarehouse(r.Context(), warehouseID); err != nil {
+				return database.Warehouse{}, err
+			}
+		}
+		return q.UpdateWarehouse(r.Context(), database.UpdateWarehouseParams{
+			ID:        warehouseID,
+			Code:      req.Code,
+			Name:      req.Name,
+			IsDefault: req.IsDefault,
+		})
+	})
+	if err != nil {
+		writeWarehouseError(w, err)
+		return
+	}
+	logger.Log.Info("warehouse updated", zap.String("warehouseID", warehouse.ID.String()), zap.String("code", warehouse.Code))
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(warehouseResponse(warehouse)); err != nil {
+		http.Error(w, "failed to encode warehouse", http.StatusInternalServerError)
+		return
+	}
+}
+
+// @Summary Delete a warehouse
+// @Description admin can delete a warehouse that never held stock. The default warehouse cannot be deleted
+// @Tags admin
+// @Param warehouseID path string true "warehouseID"
+// @Success 204 {string} string "No content"
+// @Failure 400 {object} string "Bad Request - Invalid input"
+// @Failure 401 {object} string "Unauthorized - Missing/invalid credentials"
+// @Failure 403 {object} string "Forbidden - Insufficient permissions"
+// @Failure 404 {object} string "Not Found - Resource doesn't exist"
+// @Failure 409 {object} string "Conflict - warehouse is the default or has stock movements"
+// @Failure 500 {object} string "Internal Server Error"
+// @Router /api/v1/admin/warehouses/{warehouseID} [delete]
+// @Security BearerAuth
+func (cfg *APIConfig) DeleteWarehouseHandler(w http.ResponseWriter, r *http.Request) {
+	logger.Log.Info("entered delete warehouse handler")
+
+	warehouseID, err := uuid.Parse(r.PathValue("warehouseID"))
+	if err != nil {
+		http.Error(w, "Invalid warehouse id", http.StatusBadRequest)
+		return
+	}
+
+	warehouse, err := cfg.DB.GetWarehouseByID(r.Context(), warehouseID)
+	if errors.Is(err, sql.ErrNoRows) {
+		http.Error(w, "warehouse not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		logger.Log.Error("failed to fetch warehouse", zap.String("warehouseID", warehouseID.String()), zap.Error(err))
+		http.Error(w, "databse operation failed", http.StatusInternalServerError)
+		return
+	}
+	if warehouse.IsDefault {
+		http.Error(w, errDefaultWarehouse.Error(), http.StatusConflict)
+		return
+	}
+
+	deleted, err := cfg.DB.DeleteWarehouse(r.Context(), warehouseID)
+	if isPQError(err, pqForeignKeyViolation) {
+		http.Error(w, "warehouse has stock movements", http.StatusConflict)
+		return
+	}
+	if err != nil {
+		logger.Log.Error("failed to delete warehouse", zap.String("warehouseID", warehouseID.String()), zap.Error(err))
+		http.Error(w, "databse deletion failed", http.StatusInternalServerError)
+		return
+	}
+	if deleted == 0 {
+		// made the default since it was fetched
+		http.Error(w, errDefaultWarehouse.Error(), http.StatusConflict)
+		return
+	}
+	logger.Log.Info("warehouse deleted", zap.String("warehouseID", warehouseID.String()))
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// withWarehouses runs write in a transaction, so moving the default flag
+// from one warehouse to another is never seen half done.
+func (cfg *APIConfig) withWarehouses(ctx context.Context, write func(*database.Queries) (database.Warehouse, error)) (database.Warehouse, error) {
+	tx, err := cfg.DBConn.BeginTx(ctx, nil)
+	if err != nil {
+		return database.Warehouse{}, err
+	}
+	defer tx.Rollback()
+	qtx := cfg.txQueries(tx)
+
+	warehouse, err := write(qtx)
+	if err != nil {
+		return database.Warehouse{}, err
+	}
+	if err := tx.Commit(); err != nil {
+		return database.Warehouse{}, err
+	}
+	return warehouse, nil
+}
+
+func writeWarehouseError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, errWarehouseNotFound):
+		http.Error(w, err.Error(), http.StatusNotFound)
+	case errors.Is(err, errDefaultWarehouse):
+		http.Error(w, err.Error(), http.StatusConflict)
+	case pqConstraint(err, pqUniqueViolation) == "warehouses_code_key":
+		http.Error(w, "a warehouse with this code already exists", http.StatusConflict)
+	case isPQError(err, pqUniqueViolation):
+		http.Error(w, "another warehouse was made the default at the same time", http.StatusConflict)
+	default:
+		logger.Log.Error("failed to write warehouse", zap.Error(err))
+		http.Error(w, "databse operation failed", http.StatusInternalServerError)
+	}
+}
+
+func decodeWarehouseRequest(w http.ResponseWriter, r *http.Request) (models.WarehouseRequest, bool) {
+	var req models.WarehouseRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return req, false
+	}
+	req.Name = strings.TrimSpace(req.Name)
+	if req.Name == "" {
+		http.Error(w, "name is required", http.StatusBadRequest)
+		return req, false
+	}
+	req.Code = strings.ToUpper(strings.TrimSpace(req.Code))
+	if !warehouseCodePattern.MatchString(req.Code) {
+		http.Error(w, "code must be 1 to 16 letters, digits, dashes or underscores, starting with a letter or digit", http.StatusBadRequest)
+		return req, false
+	}
+	return req, true
+}
+
+func warehouseResponse(warehouse database.Warehouse) models.WarehouseResponse {
+	return models.WarehouseResponse{
+		ID:        warehouse.ID,
+		Code:      warehouse.Code,
+		Name:      warehouse.Name,
+		IsDefault: warehouse.IsDefault,
+		CreatedAt: warehouse.CreatedAt,
+		UpdatedAt: warehouse.UpdatedAt,
+	}
+}
@@ -0,0 +1,263 @@
+// Package config loads the server configuration. Values come from, in
+// increasing order of precedence: the defaults below, an optional YAML file,
+// a .env file and the process environment.
+package config
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"time"
+
+	"go.yaml.in/yaml/v3"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// MinSecretLength is the shortest accepted token signing secret, 32 bytes
+// is the key size of HS256.
+const MinSecretLength = 32
+
+// Config is the whole server configuration. The env tag names the
+// environment variable of a field, the redact tag marks values that config
+// print must not show.
+type Config struct {
+	Server   ServerConfig   `yaml:"server"`
+	Database DatabaseConfig `yaml:"database"`
+	Auth     AuthConfig     `yaml:"auth"`
+	Products ProductsConfig `yaml:"products"`
+	Media    MediaConfig    `yaml:"media"`
+	Tracing  TracingConfig  `yaml:"tracing"`
+}
+
+type ServerConfig struct {
+	Addr string `yaml:"addr" env:"HTTP_ADDR"`
+	// CORSOrigin is the origin browsers may call the API from, "*" for any.
+	CORSOrigin string `yaml:"cors_origin" env:"CORS_ORIGIN"`
+	// ReadHeaderTimeout bounds how long a client may take to send the
+	// request headers, ReadTimeout the whole request including the body.
+	ReadHeaderTimeout time.Duration `yaml:"read_header_timeout" env:"HTTP_READ_HEADER_TIMEOUT"`
+	ReadTimeout       time.Duration `yaml:"read_timeout" env:"HTTP_READ_TIMEOUT"`
+	// WriteTimeout runs from the end of the request headers to the end of
+	// the response, so it also has to cover reading image uploads.
+	WriteTimeout time.Duration `yaml:"write_timeout" env:"HTTP_WRITE_TIMEOUT"`
+	IdleTimeout  time.Duration `yaml:"idle_timeout" env:"HTTP_IDLE_TIMEOUT"`
+	// ShutdownTimeout is how long in-flight requests may take to finish
+	// after SIGINT or SIGTERM before their connections are closed.
+	ShutdownTimeout time.Duration `yaml:"shutdown_timeout" env:"SHUTDOWN_TIMEOUT"`
+	// ShutdownDelay keeps serving after /readyz turned not ready, giving load
+	// balancers time to notice before the listener closes.
+	ShutdownDelay  time.Duration `yaml:"shutdown_delay" env:"SHUTDOWN_DELAY"`
+	MaxHeaderBytes int           `yaml:"max_header_bytes" env:"HTTP_MAX_HEADER_BYTES"`
+	// MaxBodyBytes caps request bodies. Image uploads have their own limit.
+	MaxBodyBytes int `yaml:"max_body_bytes" env:"HTTP_MAX_BODY_BYTES"`
+}
+
+type DatabaseConfig struct {
+	// URL is a postgres:// URL or a key=value connection string, the two
+	// forms lib/pq accepts.
+	URL string `yaml:"url" env:"DB_URL" redact:"password"`
+}
+
+type AuthConfig struct {
+	Secret          string        `yaml:"secret" env:"SECRET" redact:"true"`
+	AccessTokenTTL  time.Duration `yaml:"access_token_ttl" env:"ACCESS_TOKEN_TTL"`
+	RefreshTokenTTL time.Duration `yaml:"refresh_token_ttl" env:"REFRESH_TOKEN_TTL"`
+	BcryptCost      int           `yaml:"bcrypt_cost" env:"BCRYPT_COST"`
+}
+
+type ProductsConfig struct {
+	// RequireIfMatch rejects product writes without an If-Match header.
+	RequireIfMatch bool `yaml:"require_if_match" env:"REQUIRE_IF_MATCH"`
+	// TrashRetention is how long deleted products stay restorable.
+	TrashRetention     time.Duration `yaml:"trash_retention" env:"TRASH_RETENTION"`
+	TrashPurgeInterval time.Duration `yaml:"trash_purge_interval" env:"TRASH_PURGE_INTERVAL"`
+}
+
+type MediaConfig struct {
+	Dir string `yaml:"dir" env:"MEDIA_DIR"`
+	// BaseURL prefixes image URLs in responses. The files are served under
+	// /media, point BaseURL elsewhere when a proxy or CDN serves them.
+	BaseURL string `yaml:"base_url" env:"MEDIA_BASE_URL"`
+}
+
+type TracingConfig struct {
+	// Exporter is where spans go: "none", "stdout" or "otlp".
+	Exporter string `yaml:"exporter" env:"TRACING_EXPORTER"`
+	// OTLPEndpoint is the base URL of the OTLP/HTTP collector, spans are
+	// sent to its /v1/traces.
+	OTLPEndpoint string `yaml:"otlp_endpoint" env:"TRACING_OTLP_ENDPOINT"`
+	// SampleRatio is the share of new traces recorded. Requests carrying a
+	// traceparent follow the sampling decision of their caller.
+	SampleRatio float64 `yaml:"sample_ratio" env:"TRACING_SAMPLE_RATIO"`
+	ServiceName string  `yaml:"service_name" env:"OTEL_SERVICE_NAME"`
+}
+
+// Default returns the configuration used for everything that is not set.
+// There is no default database URL or secret.
+func Default() Config {
+	return Config{
+		Server: ServerConfig{
+			Addr:              ":8090",
+			CORSOrigin:        "http://localhost:5173",
+			ReadHeaderTimeout: 5 * time.Second,
+			ReadTimeout:       30 * time.Second,
+			WriteTimeout:      60 * time.Second,
+			IdleTimeout:       2 * time.Minute,
+			ShutdownTimeout:   20 * time.Second,
+			MaxHeaderBytes:    64 << 10,
+			MaxBodyBytes:      1 << 20,
+		},
+		Auth: AuthConfig{
+			AccessTokenTTL:  time.Hour,
+			RefreshTokenTTL: 30 * 24 * time.Hour,
+			BcryptCost:      bcrypt.DefaultCost,
+		},
+		Products: ProductsConfig{
+			TrashRetention:     30 * 24 * time.Hour,
+			TrashPurgeInterval: time.Hour,
+		},
+		Media: MediaConfig{
+			Dir:     "media",
+			BaseURL: "/media",
+		},
+		Tracing: TracingConfig{
+			Exporter:     "none",
+			OTLPEndpoint: "http://localhost:4318",
+			SampleRatio:  1,
+			ServiceName:  "productapi",
+		},
+	}
+}
+
+// Load builds the configuration. yamlPath may be empty for no YAML file, a
+// missing envFile is skipped. The result is validated.
+func Load(yamlPath, envFile string) (Config, error) {
+	return load(yamlPath, envFile, os.LookupEnv)
+}
+
+// load is Load with the process environment read through lookupEnv.
+func load(yamlPath, envFile string, lookupEnv func(string) (string, bool)) (Config, error) {
+	cfg := Default()
+	if yamlPath != "" {
+		if err := loadYAML(yamlPath, &cfg); err != nil {
+			return cfg, err
+		}
+	}
+	dotenv, err := readDotenv(envFile)
+	if err != nil {
+		return cfg, err
+	}
+	lookup := func(name string) (string, bool) {
+		if value, ok := lookupEnv(name); ok {
+			return value, true
+		}
+		value, ok := dotenv[name]
+		return value, ok
+	}
+	if err := applyEnv(&cfg, lookup); err != nil {
+		return cfg, err
+	}
+	return cfg, cfg.Validate()
+}
+
+func loadYAML(path string, cfg *Config) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("config file: %w", err)
+	}
+	defer f.Close()
+	dec := yaml.NewDecoder(f)
+	// a misspelled key would otherwise be ignored silently
+	dec.KnownFields(true)
+	if err := dec.Decode(cfg); err != nil {
+		return fmt.Errorf("config file %s: %w", path, err)
+	}
+	return nil
+}
+
+// Validate reports every invalid value at once.
+func (c Config) Validate() error {
+	var errs []error
+	if c.Server.Addr == "" {
+		errs = append(errs, errors.New("HTTP_ADDR must not be empty"))
+	}
+	if c.Server.CORSOrigin != "*" {
+		u, err := url.Parse(c.Server.CORSOrigin)
+		if err != nil || u.Scheme == "" || u.Host == "" || (u.Path != "" && u.Path != "/") {
+			errs = append(errs, errors.New(`CORS_ORIGIN must be an origin such as https://shop.example.com, or "*"`))
+		}
+	}
+	for _, timeout := range []struct {
+		name string
+		d    time.Duration
+	}{
+		{"HTTP_READ_HEADER_TIMEOUT", c.Server.ReadHeaderTimeout},
+		{"HTTP_READ_TIMEOUT", c.Server.ReadTimeout},
+		{"HTTP_WRITE_TIMEOUT", c.Server.WriteTimeout},
+		{"HTTP_IDLE_TIMEOUT", c.Server.IdleTimeout},
+		{"SHUTDOWN_TIMEOUT", c.Server.ShutdownTimeout},
+	} {
+		if timeout.d <= 0 {
+			errs = append(errs, fmt.Errorf("%s must be positive", timeout.name))
+		}
+	}
+	if c.Server.ShutdownDelay < 0 {
+		errs = append(errs, errors.New("SHUTDOWN_DELAY must not be negative"))
+	}
+	if c.Server.ReadHeaderTimeout > c.Server.ReadTimeout {
+		errs = append(errs, errors.New("HTTP_READ_HEADER_TIMEOUT must not be longer than HTTP_READ_TIMEOUT"))
+	}
+	if c.Server.MaxHeaderBytes < 4<<10 {
+		errs = append(errs, errors.New("HTTP_MAX_HEADER_BYTES must be at least 4096"))
+	}
+	if c.Server.MaxBodyBytes < 1<<10 {
+		errs = append(errs, errors.New("HTTP_MAX_BODY_BYTES must be at least 1024"))
+	}
+	if c.Database.URL == "" {
+		errs = append(errs, errors.New("DB_URL is required"))
+	} else if err := validateDSN(c.Database.URL); err != nil {
+		errs = append(errs, fmt.Errorf("DB_URL must be a postgres:// URL or key=value connection string: %v", err))
+	}
+	if len(c.Auth.Secret) < MinSecretLength {
+		errs = append(errs, fmt.Errorf("SECRET must be at least %d bytes long", MinSecretLength))
+	}
+	if c.Auth.AccessTokenTTL <= 0 {
+		errs = append(errs, errors.New("ACCESS_TOKEN_TTL must be positive"))
+	}
+	if c.Auth.RefreshTokenTTL <= c.Auth.AccessTokenTTL {
+		errs = append(errs, errors.New("REFRESH_TOKEN_TTL must be longer than ACCESS_TOKEN_TTL"))
+	}
+	if c.Auth.BcryptCost < bcrypt.DefaultCost || c.Auth.BcryptCost > bcrypt.MaxCost {
+		errs = append(errs, fmt.Errorf("BCRYPT_COST must be between %d and %d", bcrypt.DefaultCost, bcrypt.MaxCost))
+	}
+	if c.Products.TrashRetention <= 0 {
+		errs = append(errs, errors.New("TRASH_RETENTION must be positive"))
+	}
+	if c.Products.TrashPurgeInterval <= 0 {
+		errs = append(errs, errors.New("TRASH_PURGE_INTERVAL must be positive"))
+	}
+	if c.Media.Dir == "" {
+		errs = append(errs, errors.New("MEDIA_DIR must not be empty"))
+	}
+	if c.Media.BaseURL == "" {
+		errs = append(errs, errors.New("MEDIA_BASE_URL must not be empty"))
+	}
+	switch c.Tracing.Exporter {
+	case "none", "stdout":
+	case "otlp":
+		u, err := url.Parse(c.Tracing.OTLPEndpoint)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			errs = append(errs, errors.New("TRACING_OTLP_ENDPOINT must be an http or https URL"))
+		}
+	default:
+		errs = append(errs, errors.New(`TRACING_EXPORTER must be "none", "stdout" or "otlp"`))
+	}
+	if c.Tracing.SampleRatio < 0 || c.Tracing.SampleRatio > 1 {
+		errs = append(errs, errors.New("TRACING_SAMPLE_RATIO must be between 0 and 1"))
+	}
+	if c.Tracing.ServiceName == "" {
+		errs = append(errs, errors.New("OTEL_SERVICE_NAME must not be empty"))
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,476 @@
+package config
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+const testSecret = "0123456789abcdef0123456789abcdef"
+
+// writeFile writes content to name in a temporary directory and returns
+// its path, or "" when content is empty.
+func writeFile(t *testing.T, name, content string) string {
+	t.Helper()
+	if content == "" {
+		return ""
+	}
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+// lookupStub stands in for the process environment.
+func lookupStub(env map[string]string) func(string) (string, bool) {
+	return func(name string) (string, bool) {
+		value, ok := env[name]
+		return value, ok
+	}
+}
+
+// validEnv returns the variables that have no default, for tests about
+// something else.
+func validEnv() map[string]string {
+	return map[string]string{
+		"DB_URL": "postgres://app:s3cret@db:5432/shop?sslmode=disable",
+		"SECRET": testSecret,
+	}
+}
+
+func TestLoadPrecedence(t *testing.T) {
+	tests := []struct {
+		name     string
+		yaml     string
+		dotenv   string
+		env      map[string]string
+		wantAddr string
+		wantTTL  time.Duration
+	}{
+		{
+			name:     "defaults",
+			wantAddr: ":8090",
+			wantTTL:  time.Hour,
+		},
+		{
+			name:     "yaml over defaults",
+			yaml:     "server:\n  addr: \":9000\"\nauth:\n  access_token_ttl: 15m\n",
+			wantAddr: ":9000",
+			wantTTL:  15 * time.Minute,
+		},
+		{
+			name:     "env file over yaml",
+			yaml:     "server:\n  addr: \":9000\"\nauth:\n  access_token_ttl: 15m\n",
+			dotenv:   "HTTP_ADDR=:9100\n",
+			wantAddr: ":9100",
+			wantTTL:  15 * time.Minute,
+		},
+		{
+			name:     "environment over env file",
+			yaml:     "server:\n  addr: \":9000\"\nauth:\n  access_token_ttl: 15m\n",
+			dotenv:   "HTTP_ADDR=:9100\nACCESS_TOKEN_TTL=20m\n",
+			env:      map[string]string{"HTTP_ADDR": ":9200"},
+			wantAddr: ":9200",
+			wantTTL:  20 * time.Minute,
+		},
+		{
+			name:     "environment over defaults",
+			env:      map[string]string{"HTTP_ADDR": ":9300"},
+			wantAddr: ":9300",
+			wantTTL:  time.Hour,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			env := validEnv()
+			for name, value := range tt.env {
+				env[name] = value
+			}
+			yamlPath := writeFile(t, "config.yaml", tt.yaml)
+			envFile := writeFile(t, ".env", tt.dotenv)
+
+			cfg, err := load(yamlPath, envFile, lookupStub(env))
+			if err != nil {
+				t.Fatalf("load() error = %v", err)
+			}
+			if cfg.Server.Addr != tt.wantAddr {
+				t.Errorf("Server.Addr = %q, want %q", cfg.Server.Addr, tt.wantAddr)
+			}
+			if cfg.Auth.AccessTokenTTL != tt.wantTTL {
+				t.Errorf("Auth.AccessTokenTTL = %v, want %v", cfg.Auth.AccessTokenTTL, tt.wantTTL)
+			}
+		})
+	}
+}
+
+func TestLoadRequiredFromEnvFile(t *testing.T) {
+	envFile := writeFile(t, ".env", "DB_URL=postgres://app@db/shop\nSECRET="+testSecret+"\n")
+
+	cfg, err := load("", envFile, lookupStub(nil))
+	if err != nil {
+		t.Fatalf("load() error = %v", err)
+	}
+	if cfg.Database.URL != "postgres://app@db/shop" || cfg.Auth.Secret != testSecret {
+		t.Errorf("load() = %+v, %+v, want values from the env file", cfg.Database, cfg.Auth)
+	}
+}
+
+func TestReadDotenv(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    map[string]string
+		wantErr string
+	}{
+		{
+			name:    "plain values",
+			content: "A=1\nB = two \n",
+			want:    map[string]string{"A": "1", "B": "two"},
+		},
+		{
+			name:    "comments and blank lines",
+			content: "# header\n\n  # indented\nA=1\n",
+			want:    map[string]string{"A": "1"},
+		},
+		{
+			name:    "export prefix",
+			content: "export A=1\n",
+			want:    map[string]string{"A": "1"},
+		},
+		{
+			name:    "inline comment",
+			content: "A=value # note\nB=value\t # note\n",
+			want:    map[string]string{"A": "value", "B": "value"},
+		},
+		{
+			name:    "hash inside value",
+			content: "A=abc#def\n",
+			want:    map[string]string{"A": "abc#def"},
+		},
+		{
+			name:    "hash inside quotes",
+			content: "A=\"x # y\"\nB='x # y'\n",
+			want:    map[string]string{"A": "x # y", "B": "x # y"},
+		},
+		{
+			name:    "empty value",
+			content: "A=\nB=\"\"\n",
+			want:    map[string]string{"A": "", "B": ""},
+		},
+		{
+			name:    "equals sign in value",
+			content: "DB_URL=postgres://db/shop?sslmode=disable\n",
+			want:    map[string]string{"DB_URL": "postgres://db/shop?sslmode=disable"},
+		},
+		{
+			name:    "multi-line double quoted",
+			content: "KEY=\"-----BEGIN KEY-----\nabc   \n-----END KEY-----\"\nNEXT=1\n",
+			want:    map[string]string{"KEY": "-----BEGIN KEY-----\nabc\n-----END KEY-----", "NEXT": "1"},
+		},
+		{
+			name:    "multi-line keeps comment lines",
+			content: "A=\"one\n# two\nthree\"\n",
+			want:    map[string]string{"A": "one\n# two\nthree"},
+		},
+		{
+			name:    "lone double quote opens a multi-line value",
+			content: "A=\"\nx\"\n",
+			want:    map[string]string{"A": "\nx"},
+		},
+		{
+			name:    "later key wins",
+			content: "A=1\nA=2\n",
+			want:    map[string]string{"A": "2"},
+		},
+		{
+			name:    "unterminated quote",
+			content: "A=1\nB=\"open\nstill open\n",
+			wantErr: ":2: unterminated quoted value",
+		},
+		{
+			name:    "missing equals sign",
+			content: "A=1\nB\n",
+			wantErr: ":2: expected KEY=value",
+		},
+		{
+			name:    "missing key",
+			content: "=1\n",
+			wantErr: ":1: expected KEY=value",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := readDotenv(writeFile(t, ".env", tt.content))
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("readDotenv() error = %v, want it to contain %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("readDotenv() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("readDotenv() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReadDotenvMissingFile(t *testing.T) {
+	for _, path := range []string{"", filepath.Join(t.TempDir(), "missing.env")} {
+		got, err := readDotenv(path)
+		if err != nil || len(got) != 0 {
+			t.Errorf("readDotenv(%q) = %v, %v, want no values", path, got, err)
+		}
+	}
+}
+
+func TestLoadValidation(t *testing.T) {
+	tests := []struct {
+		name  string
+		yaml  string
+		env   map[string]string
+		unset []string
+		want  []string
+	}{
+		{
+			name:  "missing database url",
+			unset: []string{"DB_URL"},
+			want:  []string{"DB_URL is required"},
+		},
+		{
+			name: "short secret",
+			env:  map[string]string{"SECRET": "too-short"},
+			want: []string{"SECRET must be at least 32 bytes long"},
+		},
+		{
+			name:  "every error at once",
+			unset: []string{"DB_URL", "SECRET"},
+			env:   map[string]string{"BCRYPT_COST": "4"},
+			want: []string{
+				"DB_URL is required",
+				"SECRET must be at least 32 bytes long",
+				"BCRYPT_COST must be between 10 and 31",
+			},
+		},
+		{
+			name: "refresh not longer than access",
+			env:  map[string]string{"ACCESS_TOKEN_TTL": "2h", "REFRESH_TOKEN_TTL": "2h"},
+			want: []string{"REFRESH_TOKEN_TTL must be longer than ACCESS_TOKEN_TTL"},
+		},
+		{
+			name: "non-positive timeout",
+			env:  map[string]string{"HTTP_WRITE_TIMEOUT": "0s", "SHUTDOWN_DELAY": "-1s"},
+			want: []string{"HTTP_WRITE_TIMEOUT must be positive", "SHUTDOWN_DELAY must not be negative"},
+		},
+		{
+			name: "header timeout longer than read timeout",
+			env:  map[string]string{"HTTP_READ_HEADER_TIMEOUT": "1m", "HTTP_READ_TIMEOUT": "30s"},
+			want: []string{"HTTP_READ_HEADER_TIMEOUT must not be longer than HTTP_READ_TIMEOUT"},
+		},
+		{
+			name: "cors origin with a path",
+			env:  map[string]string{"CORS_ORIGIN": "https://shop.example.com/app"},
+			want: []string{"CORS_ORIGIN must be an origin"},
+		},
+		{
+			name: "unknown exporter",
+			env:  map[string]string{"TRACING_EXPORTER": "jaeger"},
+			want: []string{`TRACING_EXPORTER must be "none", "stdout" or "otlp"`},
+		},
+		{
+			name: "otlp endpoint without scheme",
+			env:  map[string]string{"TRACING_EXPORTER": "otlp", "TRACING_OTLP_ENDPOINT": "collector:4318"},
+			want: []string{"TRACING_OTLP_ENDPOINT must be an http or https URL"},
+		},
+		{
+			name: "sample ratio out of range",
+			env:  map[string]string{"TRACING_SAMPLE_RATIO": "1.5"},
+			want: []string{"TRACING_SAMPLE_RATIO must be between 0 and 1"},
+		},
+		{
+			name: "unparsable values",
+			env: map[string]string{
+				"HTTP_READ_TIMEOUT": "30",
+				"BCRYPT_COST":       "high",
+				"REQUIRE_IF_MATCH":  "yes please",
+			},
+			want: []string{
+				`HTTP_READ_TIMEOUT: must be a duration such as "90m" or "720h"`,
+				"BCRYPT_COST: must be an integer",
+				"REQUIRE_IF_MATCH: must be true or false",
+			},
+		},
+		{
+			name: "database url with another scheme",
+			env:  map[string]string{"DB_URL": "mysql://app:s3cret@db/shop"},
+			want: []string{"DB_URL must be a postgres:// URL or key=value connection string"},
+		},
+		{
+			name: "database url that is neither form",
+			env:  map[string]string{"DB_URL": "localhost"},
+			want: []string{"DB_URL must be a postgres:// URL or key=value connection string"},
+		},
+		{
+			name: "unknown yaml key",
+			yaml: "server:\n  adr: \":9000\"\n",
+			want: []string{"field adr not found"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			env := validEnv()
+			for _, name := range tt.unset {
+				delete(env, name)
+			}
+			for name, value := range tt.env {
+				env[name] = value
+			}
+
+			_, err := load(writeFile(t, "config.yaml", tt.yaml), "", lookupStub(env))
+			if err == nil {
+				t.Fatalf("load() error = nil, want %q", tt.want)
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(err.Error(), want) {
+					t.Errorf("load() error = %q, want it to contain %q", err, want)
+				}
+			}
+		})
+	}
+}
+
+func TestLoadDatabaseURLForms(t *testing.T) {
+	for _, dbURL := range []string{
+		"postgres://app:s3cret@db:5432/shop?sslmode=disable",
+		"postgresql://app@db/shop",
+		"host=db user=app password=s3cret dbname=shop sslmode=disable",
+		"host=db password='with space' dbname=shop",
+	} {
+		env := validEnv()
+		env["DB_URL"] = dbURL
+		if _, err := load("", "", lookupStub(env)); err != nil {
+			t.Errorf("load() with DB_URL %q error = %v", dbURL, err)
+		}
+	}
+}
+
+func TestRedacted(t *testing.T) {
+	tests := []struct {
+		name       string
+		dbURL      string
+		secret     string
+		wantDB     string
+		wantSecret string
+	}{
+		{
+			name:       "password replaced",
+			dbURL:      "postgres://app:s3cret@db:5432/shop?sslmode=disable",
+			secret:     testSecret,
+			wantDB:     "postgres://app:xxxxx@db:5432/shop?sslmode=disable",
+			wantSecret: "[redacted]",
+		},
+		{
+			name:       "no password",
+			dbURL:      "postgres://app@db:5432/shop",
+			secret:     testSecret,
+			wantDB:     "postgres://app@db:5432/shop",
+			wantSecret: "[redacted]",
+		},
+		{
+			name:       "password query parameter",
+			dbURL:      "postgres://app@localhost/shop?password=hunter2&sslmode=disable",
+			wantDB:     "postgres://app@localhost/shop?password=xxxxx&sslmode=disable",
+			wantSecret: "",
+		},
+		{
+			name:       "key/value password",
+			dbURL:      "host=localhost user=app password=hunter2 dbname=shop",
+			wantDB:     "host=localhost user=app password=xxxxx dbname=shop",
+			wantSecret: "",
+		},
+		{
+			name:       "key/value quoted password",
+			dbURL:      `host=localhost password = 'hun ter\'2' dbname=shop`,
+			wantDB:     "host=localhost password = xxxxx dbname=shop",
+			wantSecret: "",
+		},
+		{
+			name:       "key/value without password",
+			dbURL:      "host=localhost  user=app\tdbname=shop",
+			wantDB:     "host=localhost  user=app\tdbname=shop",
+			wantSecret: "",
+		},
+		{
+			name:       "unset values stay empty",
+			wantDB:     "",
+			wantSecret: "",
+		},
+		{
+			name:       "unparsable url",
+			dbURL:      "postgres://app:s3cret@db:port/shop",
+			wantDB:     "[redacted]",
+			wantSecret: "",
+		},
+		{
+			name:       "unparsable key/value",
+			dbURL:      "host=localhost password='hunter2",
+			wantDB:     "[redacted]",
+			wantSecret: "",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := Default()
+			cfg.Database.URL = tt.dbURL
+			cfg.Auth.Secret = tt.secret
+
+			got := cfg.Redacted()
+			if got.Database.URL != tt.wantDB {
+				t.Errorf("Database.URL = %q, want %q", got.Database.URL, tt.wantDB)
+			}
+			if got.Auth.Secret != tt.wantSecret {
+				t.Errorf("Auth.Secret = %q, want %q", got.Auth.Secret, tt.wantSecret)
+			}
+			if cfg.Database.URL != tt.dbURL || cfg.Auth.Secret != tt.secret {
+				t.Errorf("Redacted() changed the original configuration")
+			}
+		})
+	}
+}
+
+func TestPrintRedacts(t *testing.T) {
+	tests := []struct {
+		dbURL string
+		want  string
+	}{
+		{dbURL: "postgres://app:hunter2@db:5432/shop", want: "postgres://app:xxxxx@db:5432/shop"},
+		{dbURL: "postgres://app@localhost/shop?password=hunter2", want: "postgres://app@localhost/shop?password=xxxxx"},
+		{dbURL: "host=localhost user=app password=hunter2 dbname=shop", want: "host=localhost user=app password=xxxxx dbname=shop"},
+	}
+	for _, tt := range tests {
+		cfg := Default()
+		cfg.Database.URL = tt.dbURL
+		cfg.Auth.Secret = testSecret
+
+		var buf bytes.Buffer
+		if err := cfg.Print(&buf); err != nil {
+			t.Fatalf("Print() error = %v", err)
+		}
+		out := buf.String()
+		for _, leaked := range []string{"hunter2", testSecret} {
+			if strings.Contains(out, leaked) {
+				t.Errorf("Print() output contains %q:\n%s", leaked, out)
+			}
+		}
+		if !strings.Contains(out, "url: "+tt.want+"\n") {
+			t.Errorf("Print() output lacks DB_URL %q:\n%s", tt.want, out)
+		}
+	}
+}
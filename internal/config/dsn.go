@@ -0,0 +1,136 @@
+package config
+
+import (
+	"errors"
+	"net/url"
+	"strings"
+	"unicode"
+
+	"github.com/lib/pq"
+)
+
+// dsnParam is one key=value pair of a connection string. start and end
+// delimit the raw value in the string, quotes included.
+type dsnParam struct {
+	key, value string
+	start, end int
+}
+
+// isURLDSN reports whether dsn is in URL form rather than key=value form.
+func isURLDSN(dsn string) bool {
+	return strings.Contains(dsn, "://")
+}
+
+// validateDSN accepts the two forms of connection string lib/pq does: a
+// postgres:// or postgresql:// URL and key=value pairs.
+func validateDSN(dsn string) error {
+	if isURLDSN(dsn) {
+		_, err := pq.ParseURL(dsn)
+		return err
+	}
+	params, err := parseKeyValueDSN(dsn)
+	if err != nil {
+		return err
+	}
+	if len(params) == 0 {
+		return errors.New("no parameters")
+	}
+	return nil
+}
+
+// parseKeyValueDSN splits a connection string such as
+// "host=localhost user=app password='a b'" the way lib/pq does. Values may
+// be single quoted, a backslash escapes the next character.
+func parseKeyValueDSN(dsn string) ([]dsnParam, error) {
+	var params []dsnParam
+	r := []rune(dsn)
+	// positions are kept in bytes so callers can slice dsn
+	offset := func(i int) int { return len(string(r[:i])) }
+	i := 0
+	skipSpace := func() {
+		for i < len(r) && unicode.IsSpace(r[i]) {
+			i++
+		}
+	}
+	for {
+		skipSpace()
+		if i == len(r) {
+			return params, nil
+		}
+		keyStart := i
+		for i < len(r) && r[i] != '=' && !unicode.IsSpace(r[i]) {
+			i++
+		}
+		key := string(r[keyStart:i])
+		skipSpace()
+		if i == len(r) || r[i] != '=' || key == "" {
+			return nil, errors.New(`expected key=value`)
+		}
+		i++
+		skipSpace()
+
+		start := i
+		var value strings.Builder
+		quoted := i < len(r) && r[i] == '\''
+		if quoted {
+			i++
+		}
+		for {
+			if i == len(r) {
+				if quoted {
+					return nil, errors.New("unterminated quoted value")
+				}
+				break
+			}
+			c := r[i]
+			if quoted && c == '\'' {
+				i++
+				break
+			}
+			if !quoted && unicode.IsSpace(c) {
+				break
+			}
+			if c == '\\' {
+				i++
+				if i == len(r) {
+					return nil, errors.New("missing character after backslash")
+				}
+				c = r[i]
+			}
+			value.WriteRune(c)
+			i++
+		}
+		params = append(params, dsnParam{key: key, value: value.String(), start: offset(start), end: offset(i)})
+	}
+}
+
+// redactDSN replaces the password of a connection string in either form,
+// whether it is in the user info of a URL, a password query parameter or
+// a password=value pair. A string that cannot be parsed is replaced whole.
+func redactDSN(dsn string) string {
+	if isURLDSN(dsn) {
+		u, err := url.Parse(dsn)
+		if err != nil {
+			return redacted
+		}
+		if _, ok := u.User.Password(); ok {
+			u.User = url.UserPassword(u.User.Username(), redactedPassword)
+		}
+		if query := u.Query(); query.Has("password") {
+			query.Set("password", redactedPassword)
+			u.RawQuery = query.Encode()
+		}
+		return u.String()
+	}
+	params, err := parseKeyValueDSN(dsn)
+	if err != nil {
+		return redacted
+	}
+	// replace from the end so earlier offsets stay valid
+	for i := len(params) - 1; i >= 0; i-- {
+		if params[i].key == "password" {
+			dsn = dsn[:params[i].start] + redactedPassword + dsn[params[i].end:]
+		}
+	}
+	return dsn
+}
@@ -0,0 +1,43 @@
+package config
+
+import (
+	"io"
+	"reflect"
+
+	"go.yaml.in/yaml/v3"
+)
+
+const (
+	redacted         = "[redacted]"
+	redactedPassword = "xxxxx"
+)
+
+// Redacted returns a copy of the configuration that is safe to show: values
+// tagged redact:"true" are replaced and connection strings tagged
+// redact:"password" lose their password. Unset values stay empty so they
+// can be told apart.
+func (c Config) Redacted() Config {
+	walk(reflect.ValueOf(&c).Elem(), func(field reflect.StructField, value reflect.Value) {
+		if value.Kind() != reflect.String || value.String() == "" {
+			return
+		}
+		switch field.Tag.Get("redact") {
+		case "true":
+			value.SetString(redacted)
+		case "password":
+			value.SetString(redactDSN(value.String()))
+		}
+	})
+	return c
+}
+
+// Print writes the effective configuration as YAML with secrets redacted.
+// The output is a valid config file apart from the redacted values.
+func (c Config) Print(w io.Writer) error {
+	enc := yaml.NewEncoder(w)
+	enc.SetIndent(2)
+	if err := enc.Encode(c.Redacted()); err != nil {
+		return err
+	}
+	return enc.Close()
+}
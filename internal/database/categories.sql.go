@@ -0,0 +1,284 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.30.0
+// source: categories.sql
+
+package database
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+const addProductCategories = `-- name: AddProductCategories :exec
+INSERT INTO product_categories (product_id, category_id)
+SELECT $1, unnest($2::uuid[])
+ON CONFLICT DO NOTHING
+`
+
+type AddProductCategoriesParams struct {
+	ProductID   uuid.UUID
+	CategoryIds []uuid.UUID
+}
+
+func (q *Queries) AddProductCategories(ctx context.Context, arg AddProductCategoriesParams) error {
+	_, err := q.db.ExecContext(ctx, addProductCategories, arg.ProductID, pq.Array(arg.CategoryIds))
+	return err
+}
+
+const clearProductCategories = `-- name: ClearProductCategories :exec
+DELETE FROM product_categories
+WHERE product_id = $1
+`
+
+func (q *Queries) ClearProductCategories(ctx context.Context, productID uuid.UUID) error {
+	_, err := q.db.ExecContext(ctx, clearProductCategories, productID)
+	return err
+}
+
+const createCategory = `-- name: CreateCategory :one
+INSERT INTO categories (parent_id, name, slug, path)
+VALUES ($1, $2, $3, $4)
+RETURNING id, parent_id, name, slug, path, created_at, updated_at
+`
+
+type CreateCategoryParams struct {
+	ParentID uuid.NullUUID
+	Name     string
+	Slug     string
+	Path     string
+}
+
+func (q *Queries) CreateCategory(ctx context.Context, arg CreateCategoryParams) (Category, error) {
+	row := q.db.QueryRowContext(ctx, createCategory,
+		arg.ParentID,
+		arg.Name,
+		arg.Slug,
+		arg.Path,
+	)
+	var i Category
+	err := row.Scan(
+		&i.ID,
+		&i.ParentID,
+		&i.Name,
+		&i.Slug,
+		&i.Path,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const deleteCategory = `-- name: DeleteCategory :execrows
+WITH touched AS (
+    UPDATE products p
+    SET
+        version = p.version + 1,
+        updated_at = NOW()
+    WHERE p.id IN (SELECT pc.product_id FROM product_categories pc WHERE pc.category_id = $1::uuid)
+)
+DELETE FROM categories c
+WHERE c.id = $1::uuid
+`
+
+// The products lose the category, which changes their responses.
+func (q *Queries) DeleteCategory(ctx context.Context, id uuid.UUID) (int64, error) {
+	result, err := q.db.ExecContext(ctx, deleteCategory, id)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const getCategoryByID = `-- name: GetCategoryByID :one
+SELECT id, parent_id, name, slug, path, created_at, updated_at FROM categories
+WHERE id = $1
+`
+
+func (q *Queries) GetCategoryByID(ctx context.Context, id uuid.UUID) (Category, error) {
+	row := q.db.QueryRowContext(ctx, getCategoryByID, id)
+	var i Category
+	err := row.Scan(
+		&i.ID,
+		&i.ParentID,
+		&i.Name,
+		&i.Slug,
+		&i.Path,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const listCategories = `-- name: ListCategories :many
+SELECT id, parent_id, name, slug, path, created_at, updated_at FROM categories
+ORDER BY path
+`
+
+func (q *Queries) ListCategories(ctx context.Context) ([]Category, error) {
+	rows, err := q.db.QueryContext(ctx, listCategories)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Category
+	for rows.Next() {
+		var i Category
+		if err := rows.Scan(
+			&i.ID,
+			&i.ParentID,
+			&i.Name,
+			&i.Slug,
+			&i.Path,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listCategoriesForProducts = `-- name: ListCategoriesForProducts :many
+SELECT pc.product_id, c.id, c.name, c.slug, c.path
+FROM product_categories pc
+JOIN categories c ON c.id = pc.category_id
+WHERE pc.product_id = ANY($1::uuid[])
+ORDER BY c.path
+`
+
+type ListCategoriesForProductsRow struct {
+	ProductID uuid.UUID
+	ID        uuid.UUID
+	Name      string
+	Slug      string
+	Path      string
+}
+
+func (q *Queries) ListCategoriesForProducts(ctx context.Context, productIds []uuid.UUID) ([]ListCategoriesForProductsRow, error) {
+	rows, err := q.db.QueryContext(ctx, listCategoriesForProducts, pq.Array(productIds))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListCategoriesForProductsRow
+	for rows.Next() {
+		var i ListCategoriesForProductsRow
+		if err := rows.Scan(
+			&i.ProductID,
+			&i.ID,
+			&i.Name,
+			&i.Slug,
+			&i.Path,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const lockCategoryTree = `-- name: LockCategoryTree :exec
+SELECT pg_advisory_xact_lock($1::bigint)
+`
+
+// Serializes writers that derive paths from other categories, so a
+// concurrent move cannot leave a stale path or create a cycle.
+func (q *Queries) LockCategoryTree(ctx context.Context, lockKey int64) error {
+	_, err := q.db.ExecContext(ctx, lockCategoryTree, lockKey)
+	return err
+}
+
+const moveCategoryDescendants = `-- name: MoveCategoryDescendants :exec
+UPDATE categories
+SET
+    path = $1::text || substr(path, length($2::text) + 1),
+    updated_at = NOW()
+WHERE path LIKE $2::text || '/%'
+`
+
+type MoveCategoryDescendantsParams struct {
+	NewPath string
+	OldPath string
+}
+
+func (q *Queries) MoveCategoryDescendants(ctx context.Context, arg MoveCategoryDescendantsParams) error {
+	_, err := q.db.ExecContext(ctx, moveCategoryDescendants, arg.NewPath, arg.OldPath)
+	return err
+}
+
+const touchCategoryProducts = `-- name: TouchCategoryProducts :exec
+UPDATE products
+SET
+    version = version + 1,
+    updated_at = NOW()
+WHERE id IN (
+    SELECT pc.product_id
+    FROM product_categories pc
+    JOIN categories c ON c.id = pc.category_id
+    WHERE c.path = $1::text OR c.path LIKE $1::text || '/%'
+)
+`
+
+// Product responses embed the name and path of their categories, so
+// renaming or moving a category changes every product in its subtree.
+func (q *Queries) TouchCategoryProducts(ctx context.Context, path string) error {
+	_, err := q.db.ExecContext(ctx, touchCategoryProducts, path)
+	return err
+}
+
+const updateCategory = `-- name: UpdateCategory :one
+UPDATE categories
+SET
+    parent_id = $1,
+    name = $2,
+    slug = $3,
+    path = $4,
+    updated_at = NOW()
+WHERE id = $5
+RETURNING id, parent_id, name, slug, path, created_at, updated_at
+`
+
+type UpdateCategoryParams struct {
+	ParentID uuid.NullUUID
+	Name     string
+	Slug     string
+	Path     string
+	ID       uuid.UUID
+}
+
+func (q *Queries) UpdateCategory(ctx context.Context, arg UpdateCategoryParams) (Category, error) {
+	row := q.db.QueryRowContext(ctx, updateCategory,
+		arg.ParentID,
+		arg.Name,
+		arg.Slug,
+		arg.Path,
+		arg.ID,
+	)
+	var i Category
+	err := row.Scan(
+		&i.ID,
+		&i.ParentID,
+		&i.Name,
+		&i.Slug,
+		&i.Path,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
@@ -0,0 +1,100 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.30.0
+// source: exchange_rates.sql
+
+package database
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+const deleteExchangeRate = `-- name: DeleteExchangeRate :execrows
+DELETE FROM exchange_rates
+WHERE base_currency = $1 AND quote_currency = $2
+`
+
+type DeleteExchangeRateParams struct {
+	BaseCurrency  string
+	QuoteCurrency string
+}
+
+func (q *Queries) DeleteExchangeRate(ctx context.Context, arg DeleteExchangeRateParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, deleteExchangeRate, arg.BaseCurrency, arg.QuoteCurrency)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const listExchangeRates = `-- name: ListExchangeRates :many
+SELECT base_currency, quote_currency, rate, updated_at, updated_by FROM exchange_rates
+ORDER BY base_currency, quote_currency
+`
+
+func (q *Queries) ListExchangeRates(ctx context.Context) ([]ExchangeRate, error) {
+	rows, err := q.db.QueryContext(ctx, listExchangeRates)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ExchangeRate
+	for rows.Next() {
+		var i ExchangeRate
+		if err := rows.Scan(
+			&i.BaseCurrency,
+			&i.QuoteCurrency,
+			&i.Rate,
+			&i.UpdatedAt,
+			&i.UpdatedBy,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const upsertExchangeRate = `-- name: UpsertExchangeRate :one
+INSERT INTO exchange_rates (base_currency, quote_currency, rate, updated_at, updated_by)
+VALUES ($1, $2, $3, NOW(), $4)
+ON CONFLICT (base_currency, quote_currency) DO UPDATE
+SET
+    rate = EXCLUDED.rate,
+    updated_at = EXCLUDED.updated_at,
+    updated_by = EXCLUDED.updated_by
+RETURNING base_currency, quote_currency, rate, updated_at, updated_by
+`
+
+type UpsertExchangeRateParams struct {
+	BaseCurrency  string
+	QuoteCurrency string
+	Rate          string
+	UpdatedBy     uuid.NullUUID
+}
+
+func (q *Queries) UpsertExchangeRate(ctx context.Context, arg UpsertExchangeRateParams) (ExchangeRate, error) {
+	row := q.db.QueryRowContext(ctx, upsertExchangeRate,
+		arg.BaseCurrency,
+		arg.QuoteCurrency,
+		arg.Rate,
+		arg.UpdatedBy,
+	)
+	var i ExchangeRate
+	err := row.Scan(
+		&i.BaseCurrency,
+		&i.QuoteCurrency,
+		&i.Rate,
+		&i.UpdatedAt,
+		&i.UpdatedBy,
+	)
+	return i, err
+}
@@ -0,0 +1,170 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.30.0
+// source: images.sql
+
+package database
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+const countProductImages = `-- name: CountProductImages :one
+SELECT COUNT(*) FROM product_images
+WHERE product_id = $1
+`
+
+func (q *Queries) CountProductImages(ctx context.Context, productID uuid.UUID) (int64, error) {
+	row := q.db.QueryRowContext(ctx, countProductImages, productID)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const createProductImage = `-- name: CreateProductImage :one
+INSERT INTO product_images (id, product_id, position, storage_key, thumbnail_key, content_type, size_bytes, width, height)
+VALUES (
+    $1,
+    $2,
+    (SELECT COALESCE(MAX(position) + 1, 0) FROM product_images WHERE product_id = $2),
+    $3,
+    $4,
+    $5,
+    $6,
+    $7,
+    $8
+)
+RETURNING id, product_id, position, storage_key, thumbnail_key, content_type, size_bytes, width, height, created_at
+`
+
+type CreateProductImageParams struct {
+	ID           uuid.UUID
+	ProductID    uuid.UUID
+	StorageKey   string
+	ThumbnailKey string
+	ContentType  string
+	SizeBytes    int64
+	Width        int32
+	Height       int32
+}
+
+func (q *Queries) CreateProductImage(ctx context.Context, arg CreateProductImageParams) (ProductImage, error) {
+	row := q.db.QueryRowContext(ctx, createProductImage,
+		arg.ID,
+		arg.ProductID,
+		arg.StorageKey,
+		arg.ThumbnailKey,
+		arg.ContentType,
+		arg.SizeBytes,
+		arg.Width,
+		arg.Height,
+	)
+	var i ProductImage
+	err := row.Scan(
+		&i.ID,
+		&i.ProductID,
+		&i.Position,
+		&i.StorageKey,
+		&i.ThumbnailKey,
+		&i.ContentType,
+		&i.SizeBytes,
+		&i.Width,
+		&i.Height,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const deleteProductImage = `-- name: DeleteProductImage :one
+DELETE FROM product_images
+WHERE id = $1 AND product_id = $2
+RETURNING id, product_id, position, storage_key, thumbnail_key, content_type, size_bytes, width, height, created_at
+`
+
+type DeleteProductImageParams struct {
+	ID        uuid.UUID
+	ProductID uuid.UUID
+}
+
+func (q *Queries) DeleteProductImage(ctx context.Context, arg DeleteProductImageParams) (ProductImage, error) {
+	row := q.db.QueryRowContext(ctx, deleteProductImage, arg.ID, arg.ProductID)
+	var i ProductImage
+	err := row.Scan(
+		&i.ID,
+		&i.ProductID,
+		&i.Position,
+		&i.StorageKey,
+		&i.ThumbnailKey,
+		&i.ContentType,
+		&i.SizeBytes,
+		&i.Width,
+		&i.Height,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const listImagesForProducts = `-- name: ListImagesForProducts :many
+SELECT id, product_id, position, storage_key, thumbnail_key, content_type, size_bytes, width, height, created_at FROM product_images
+WHERE product_id = ANY($1::uuid[])
+ORDER BY product_id, position, created_at
+`
+
+func (q *Queries) ListImagesForProducts(ctx context.Context, productIds []uuid.UUID) ([]ProductImage, error) {
+	rows, err := q.db.QueryContext(ctx, listImagesForProducts, pq.Array(productIds))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ProductImage
+	for rows.Next() {
+		var i ProductImage
+		if err := rows.Scan(
+			&i.ID,
+			&i.ProductID,
+			&i.Position,
+			&i.StorageKey,
+			&i.ThumbnailKey,
+			&i.ContentType,
+			&i.SizeBytes,
+			&i.Width,
+			&i.Height,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const reorderProductImages = `-- name: ReorderProductImages :execrows
+UPDATE product_images i
+SET position = o.position - 1
+FROM unnest($2::uuid[]) WITH ORDINALITY AS o(id, position)
+WHERE i.id = o.id AND i.product_id = $1
+`
+
+type ReorderProductImagesParams struct {
+	ProductID uuid.UUID
+	ImageIds  []uuid.UUID
+}
+
+// ReorderProductImages moves every image of the product to the index of its
+// id in image_ids.
+func (q *Queries) ReorderProductImages(ctx context.Context, arg ReorderProductImagesParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, reorderProductImages, arg.ProductID, pq.Array(arg.ImageIds))
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
@@ -0,0 +1,285 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.30.0
+// source: inventory.sql
+
+package database
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+const createStockMovement = `-- name: CreateStockMovement :one
+INSERT INTO stock_movements (product_id, warehouse_id, variant_id, kind, quantity, reason, actor_id, balance_after, transfer_id)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+RETURNING id, product_id, kind, quantity, reason, actor_id, balance_after, created_at, warehouse_id, transfer_id, variant_id
+`
+
+type CreateStockMovementParams struct {
+	ProductID    uuid.UUID
+	WarehouseID  uuid.UUID
+	VariantID    uuid.NullUUID
+	Kind         string
+	Quantity     int32
+	Reason       string
+	ActorID      uuid.NullUUID
+	BalanceAfter int32
+	TransferID   uuid.NullUUID
+}
+
+func (q *Queries) CreateStockMovement(ctx context.Context, arg CreateStockMovementParams) (StockMovement, error) {
+	row := q.db.QueryRowContext(ctx, createStockMovement,
+		arg.ProductID,
+		arg.WarehouseID,
+		arg.VariantID,
+		arg.Kind,
+		arg.Quantity,
+		arg.Reason,
+		arg.ActorID,
+		arg.BalanceAfter,
+		arg.TransferID,
+	)
+	var i StockMovement
+	err := row.Scan(
+		&i.ID,
+		&i.ProductID,
+		&i.Kind,
+		&i.Quantity,
+		&i.Reason,
+		&i.ActorID,
+		&i.BalanceAfter,
+		&i.CreatedAt,
+		&i.WarehouseID,
+		&i.TransferID,
+		&i.VariantID,
+	)
+	return i, err
+}
+
+const ensureStockLevel = `-- name: EnsureStockLevel :exec
+INSERT INTO stock_levels (product_id, warehouse_id, variant_id)
+VALUES ($1, $2, $3)
+ON CONFLICT DO NOTHING
+`
+
+type EnsureStockLevelParams struct {
+	ProductID   uuid.UUID
+	WarehouseID uuid.UUID
+	VariantID   uuid.NullUUID
+}
+
+func (q *Queries) EnsureStockLevel(ctx context.Context, arg EnsureStockLevelParams) error {
+	_, err := q.db.ExecContext(ctx, ensureStockLevel, arg.ProductID, arg.WarehouseID, arg.VariantID)
+	return err
+}
+
+const listProductStockLevels = `-- name: ListProductStockLevels :many
+SELECT w.id AS warehouse_id, w.code, w.name, s.variant_id, v.sku AS variant_sku, s.on_hand, s.updated_at
+FROM stock_levels s
+JOIN warehouses w ON w.id = s.warehouse_id
+LEFT JOIN product_variants v ON v.id = s.variant_id
+WHERE s.product_id = $1
+ORDER BY w.code, v.sku NULLS FIRST
+`
+
+type ListProductStockLevelsRow struct {
+	WarehouseID uuid.UUID
+	Code        string
+	Name        string
+	VariantID   uuid.NullUUID
+	VariantSku  sql.NullString
+	OnHand      int32
+	UpdatedAt   time.Time
+}
+
+func (q *Queries) ListProductStockLevels(ctx context.Context, productID uuid.UUID) ([]ListProductStockLevelsRow, error) {
+	rows, err := q.db.QueryContext(ctx, listProductStockLevels, productID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListProductStockLevelsRow
+	for rows.Next() {
+		var i ListProductStockLevelsRow
+		if err := rows.Scan(
+			&i.WarehouseID,
+			&i.Code,
+			&i.Name,
+			&i.VariantID,
+			&i.VariantSku,
+			&i.OnHand,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listStockMovements = `-- name: ListStockMovements :many
+SELECT id, product_id, kind, quantity, reason, actor_id, balance_after, created_at, warehouse_id, transfer_id, variant_id FROM stock_movements
+WHERE product_id = $1
+    AND ($2::uuid IS NULL OR warehouse_id = $2)
+    AND ($3::uuid IS NULL OR variant_id = $3)
+ORDER BY id DESC
+LIMIT $5 OFFSET $4
+`
+
+type ListStockMovementsParams struct {
+	ProductID   uuid.UUID
+	WarehouseID uuid.NullUUID
+	VariantID   uuid.NullUUID
+	PageOffset  int32
+	PageSize    int32
+}
+
+func (q *Queries) ListStockMovements(ctx context.Context, arg ListStockMovementsParams) ([]StockMovement, error) {
+	rows, err := q.db.QueryContext(ctx, listStockMovements,
+		arg.ProductID,
+		arg.WarehouseID,
+		arg.VariantID,
+		arg.PageOffset,
+		arg.PageSize,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []StockMovement
+	for rows.Next() {
+		var i StockMovement
+		if err := rows.Scan(
+			&i.ID,
+			&i.ProductID,
+			&i.Kind,
+			&i.Quantity,
+			&i.Reason,
+			&i.ActorID,
+			&i.BalanceAfter,
+			&i.CreatedAt,
+			&i.WarehouseID,
+			&i.TransferID,
+			&i.VariantID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const lockStockLevel = `-- name: LockStockLevel :one
+SELECT product_id, on_hand, updated_at, warehouse_id, variant_id FROM stock_levels
+WHERE product_id = $1
+    AND warehouse_id = $2
+    AND variant_id IS NOT DISTINCT FROM $3
+FOR UPDATE
+`
+
+type LockStockLevelParams struct {
+	ProductID   uuid.UUID
+	WarehouseID uuid.UUID
+	VariantID   uuid.NullUUID
+}
+
+func (q *Queries) LockStockLevel(ctx context.Context, arg LockStockLevelParams) (StockLevel, error) {
+	row := q.db.QueryRowContext(ctx, lockStockLevel, arg.ProductID, arg.WarehouseID, arg.VariantID)
+	var i StockLevel
+	err := row.Scan(
+		&i.ProductID,
+		&i.OnHand,
+		&i.UpdatedAt,
+		&i.WarehouseID,
+		&i.VariantID,
+	)
+	return i, err
+}
+
+const setStockLevel = `-- name: SetStockLevel :one
+UPDATE stock_levels
+SET
+    on_hand = $1,
+    updated_at = NOW()
+WHERE product_id = $2
+    AND warehouse_id = $3
+    AND variant_id IS NOT DISTINCT FROM $4
+RETURNING product_id, on_hand, updated_at, warehouse_id, variant_id
+`
+
+type SetStockLevelParams struct {
+	OnHand      int32
+	ProductID   uuid.UUID
+	WarehouseID uuid.UUID
+	VariantID   uuid.NullUUID
+}
+
+func (q *Queries) SetStockLevel(ctx context.Context, arg SetStockLevelParams) (StockLevel, error) {
+	row := q.db.QueryRowContext(ctx, setStockLevel,
+		arg.OnHand,
+		arg.ProductID,
+		arg.WarehouseID,
+		arg.VariantID,
+	)
+	var i StockLevel
+	err := row.Scan(
+		&i.ProductID,
+		&i.OnHand,
+		&i.UpdatedAt,
+		&i.WarehouseID,
+		&i.VariantID,
+	)
+	return i, err
+}
+
+const sumStockForProducts = `-- name: SumStockForProducts :many
+SELECT product_id, SUM(on_hand)::bigint AS on_hand
+FROM stock_levels
+WHERE product_id = ANY($1::uuid[])
+GROUP BY product_id
+`
+
+type SumStockForProductsRow struct {
+	ProductID uuid.UUID
+	OnHand    int64
+}
+
+func (q *Queries) SumStockForProducts(ctx context.Context, productIds []uuid.UUID) ([]SumStockForProductsRow, error) {
+	rows, err := q.db.QueryContext(ctx, sumStockForProducts, pq.Array(productIds))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []SumStockForProductsRow
+	for rows.Next() {
+		var i SumStockForProductsRow
+		if err := rows.Scan(&i.ProductID, &i.OnHand); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
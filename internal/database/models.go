@@ -6,27 +6,143 @@ package database
 
 import (
 	"database/sql"
+	"encoding/json"
 	"time"
 
 	"github.com/google/uuid"
 )
 
-type Product struct {
+type Category struct {
 	ID        uuid.UUID
+	ParentID  uuid.NullUUID
 	Name      string
+	Slug      string
+	Path      string
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+type ExchangeRate struct {
+	BaseCurrency  string
+	QuoteCurrency string
+	Rate          string
+	UpdatedAt     time.Time
+	UpdatedBy     uuid.NullUUID
+}
+
+type Product struct {
+	ID           uuid.UUID
+	Name         string
+	Price        string
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+	PostedBy     uuid.UUID
+	SearchVector sql.NullString
+	Version      int32
+	Currency     string
+	DeletedAt    sql.NullTime
+	DeletedBy    uuid.NullUUID
+	Sku          string
+	Gtin         sql.NullString
+}
+
+type ProductCategory struct {
+	ProductID  uuid.UUID
+	CategoryID uuid.UUID
+}
+
+type ProductImage struct {
+	ID           uuid.UUID
+	ProductID    uuid.UUID
+	Position     int32
+	StorageKey   string
+	ThumbnailKey string
+	ContentType  string
+	SizeBytes    int64
+	Width        int32
+	Height       int32
+	CreatedAt    time.Time
+}
+
+type ProductPriceHistory struct {
+	ID        int64
+	ProductID uuid.UUID
 	Price     string
+	Currency  string
+	ChangedBy uuid.NullUUID
+	ChangedAt time.Time
+}
+
+type ProductTag struct {
+	ProductID uuid.UUID
+	TagID     int64
+}
+
+type ProductVariant struct {
+	ID        uuid.UUID
+	ProductID uuid.UUID
+	Sku       string
+	Options   json.RawMessage
+	Price     sql.NullString
+	Currency  sql.NullString
 	CreatedAt time.Time
 	UpdatedAt time.Time
-	PostedBy  uuid.UUID
 }
 
 type RefreshToken struct {
-	Token     string
+	Token      string
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+	UserID     uuid.UUID
+	ExpiresAt  time.Time
+	RevokedAt  sql.NullTime
+	FamilyID   uuid.UUID
+	ReplacedBy sql.NullString
+}
+
+type Role struct {
+	Name        string
+	Description string
+	CreatedAt   time.Time
+}
+
+type RolePermission struct {
+	Role       string
+	Permission string
+}
+
+type Sku struct {
+	Sku       string
+	ProductID uuid.UUID
+	VariantID uuid.NullUUID
+}
+
+type StockLevel struct {
+	ProductID   uuid.UUID
+	OnHand      int32
+	UpdatedAt   time.Time
+	WarehouseID uuid.UUID
+	VariantID   uuid.NullUUID
+}
+
+type StockMovement struct {
+	ID           int64
+	ProductID    uuid.UUID
+	Kind         string
+	Quantity     int32
+	Reason       string
+	ActorID      uuid.NullUUID
+	BalanceAfter int32
+	CreatedAt    time.Time
+	WarehouseID  uuid.UUID
+	TransferID   uuid.NullUUID
+	VariantID    uuid.NullUUID
+}
+
+type Tag struct {
+	ID        int64
+	Name      string
 	CreatedAt time.Time
-	UpdatedAt time.Time
-	UserID    uuid.UUID
-	ExpiresAt time.Time
-	RevokedAt sql.NullTime
 }
 
 type User struct {
@@ -36,4 +152,14 @@ type User struct {
 	CreatedAt      time.Time
 	UpdatedAt      time.Time
 	Role           string
+	DisabledAt     sql.NullTime
+}
+
+type Warehouse struct {
+	ID        uuid.UUID
+	Code      string
+	Name      string
+	IsDefault bool
+	CreatedAt time.Time
+	UpdatedAt time.Time
 }
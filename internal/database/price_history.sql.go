@@ -0,0 +1,115 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.30.0
+// source: price_history.sql
+
+package database
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const listProductPriceHistory = `-- name: ListProductPriceHistory :many
+SELECT h.id, h.price, h.currency, h.changed_by, h.changed_at, nxt.changed_at AS effective_until
+FROM product_price_history h
+LEFT JOIN product_price_history nxt ON nxt.id = (
+    SELECT n.id
+    FROM product_price_history n
+    WHERE n.product_id = h.product_id
+        AND (n.changed_at, n.id) > (h.changed_at, h.id)
+    ORDER BY n.changed_at, n.id
+    LIMIT 1
+)
+WHERE h.product_id = $1
+ORDER BY h.changed_at DESC, h.id DESC
+LIMIT $3 OFFSET $2
+`
+
+type ListProductPriceHistoryParams struct {
+	ProductID  uuid.UUID
+	PageOffset int32
+	PageSize   int32
+}
+
+type ListProductPriceHistoryRow struct {
+	ID             int64
+	Price          string
+	Currency       string
+	ChangedBy      uuid.NullUUID
+	ChangedAt      time.Time
+	EffectiveUntil sql.NullTime
+}
+
+// Newest first. effective_until is when the next entry replaced the price,
+// NULL for the current one.
+func (q *Queries) ListProductPriceHistory(ctx context.Context, arg ListProductPriceHistoryParams) ([]ListProductPriceHistoryRow, error) {
+	rows, err := q.db.QueryContext(ctx, listProductPriceHistory, arg.ProductID, arg.PageOffset, arg.PageSize)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListProductPriceHistoryRow
+	for rows.Next() {
+		var i ListProductPriceHistoryRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Price,
+			&i.Currency,
+			&i.ChangedBy,
+			&i.ChangedAt,
+			&i.EffectiveUntil,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const recordProductPrice = `-- name: RecordProductPrice :exec
+INSERT INTO product_price_history (product_id, price, currency, changed_by, changed_at)
+SELECT $1::uuid, $2::numeric, $3::text, $4::uuid, $5::timestamptz
+WHERE NOT EXISTS (
+    SELECT 1
+    FROM (
+        SELECT h.price, h.currency
+        FROM product_price_history h
+        WHERE h.product_id = $1::uuid
+        ORDER BY h.changed_at DESC, h.id DESC
+        LIMIT 1
+    ) latest
+    WHERE latest.price = $2::numeric AND latest.currency = $3::text
+)
+`
+
+type RecordProductPriceParams struct {
+	ProductID uuid.UUID
+	Price     string
+	Currency  string
+	ChangedBy uuid.NullUUID
+	ChangedAt time.Time
+}
+
+// Appends the price unless it is already the latest entry, so writes that
+// leave the price alone do not add to the history. Run it in the transaction
+// that wrote the product: the product row lock orders concurrent writers.
+func (q *Queries) RecordProductPrice(ctx context.Context, arg RecordProductPriceParams) error {
+	_, err := q.db.ExecContext(ctx, recordProductPrice,
+		arg.ProductID,
+		arg.Price,
+		arg.Currency,
+		arg.ChangedBy,
+		arg.ChangedAt,
+	)
+	return err
+}
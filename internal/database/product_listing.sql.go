@@ -0,0 +1,214 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.30.0
+// source: product_listing.sql
+
+package database
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+const listProducts = `-- name: ListProducts :many
+WITH filtered AS NOT MATERIALIZED (
+    SELECT id, name, price, created_at, updated_at, posted_by, search_vector, version, currency, deleted_at, deleted_by, sku, gtin FROM products
+    WHERE
+        deleted_at IS NULL
+        AND ($7::text IS NULL OR name ILIKE '%' || $7::text || '%')
+        AND ($8::numeric IS NULL OR price >= $8::numeric)
+        AND ($9::numeric IS NULL OR price <= $9::numeric)
+        AND ($10::uuid IS NULL OR posted_by = $10::uuid)
+        AND ($11::timestamptz IS NULL OR created_at >= $11::timestamptz)
+        AND ($12::timestamptz IS NULL OR created_at < $12::timestamptz)
+        AND ($13::timestamptz IS NULL OR updated_at >= $13::timestamptz)
+        AND ($14::timestamptz IS NULL OR updated_at < $14::timestamptz)
+        AND ($15::uuid IS NULL OR EXISTS (
+            SELECT 1
+            FROM product_categories pc
+            JOIN categories c ON c.id = pc.category_id
+            WHERE pc.product_id = products.id
+                AND (c.id = $15::uuid
+                    OR ($16::bool AND c.path LIKE (
+                        SELECT root.path FROM categories root WHERE root.id = $15::uuid
+                    ) || '/%'))
+        ))
+        AND (COALESCE(cardinality($17::text[]), 0) = 0 OR (
+            SELECT COUNT(*)
+            FROM product_tags pt
+            JOIN tags t ON t.id = pt.tag_id
+            WHERE pt.product_id = products.id
+                AND t.name = ANY($17::text[])
+        ) >= CASE WHEN $18::bool THEN cardinality($17::text[]) ELSE 1 END)
+)
+SELECT page.id, page.name, page.price, page.created_at, page.updated_at, page.posted_by, page.search_vector, page.version, page.currency, page.deleted_at, page.deleted_by, page.sku, page.gtin FROM (
+    (SELECT id, name, price, created_at, updated_at, posted_by, search_vector, version, currency, deleted_at, deleted_by, sku, gtin FROM filtered f
+    WHERE $1::text = 'created_at'
+        AND ($2::uuid IS NULL
+            OR (f.created_at, f.id) > ($3::timestamptz, $2::uuid))
+    ORDER BY f.created_at, f.id
+    LIMIT $4)
+    UNION ALL
+    (SELECT id, name, price, created_at, updated_at, posted_by, search_vector, version, currency, deleted_at, deleted_by, sku, gtin FROM filtered f
+    WHERE $1::text = '-created_at'
+        AND ($2::uuid IS NULL
+            OR (f.created_at, f.id) < ($3::timestamptz, $2::uuid))
+    ORDER BY f.created_at DESC, f.id DESC
+    LIMIT $4)
+    UNION ALL
+    (SELECT id, name, price, created_at, updated_at, posted_by, search_vector, version, currency, deleted_at, deleted_by, sku, gtin FROM filtered f
+    WHERE $1::text = 'updated_at'
+        AND ($2::uuid IS NULL
+            OR (f.updated_at, f.id) > ($3::timestamptz, $2::uuid))
+    ORDER BY f.updated_at, f.id
+    LIMIT $4)
+    UNION ALL
+    (SELECT id, name, price, created_at, updated_at, posted_by, search_vector, version, currency, deleted_at, deleted_by, sku, gtin FROM filtered f
+    WHERE $1::text = '-updated_at'
+        AND ($2::uuid IS NULL
+            OR (f.updated_at, f.id) < ($3::timestamptz, $2::uuid))
+    ORDER BY f.updated_at DESC, f.id DESC
+    LIMIT $4)
+    UNION ALL
+    (SELECT id, name, price, created_at, updated_at, posted_by, search_vector, version, currency, deleted_at, deleted_by, sku, gtin FROM filtered f
+    WHERE $1::text = 'price'
+        AND ($2::uuid IS NULL
+            OR (f.price, f.id) > ($5::numeric, $2::uuid))
+    ORDER BY f.price, f.id
+    LIMIT $4)
+    UNION ALL
+    (SELECT id, name, price, created_at, updated_at, posted_by, search_vector, version, currency, deleted_at, deleted_by, sku, gtin FROM filtered f
+    WHERE $1::text = '-price'
+        AND ($2::uuid IS NULL
+            OR (f.price, f.id) < ($5::numeric, $2::uuid))
+    ORDER BY f.price DESC, f.id DESC
+    LIMIT $4)
+    UNION ALL
+    (SELECT id, name, price, created_at, updated_at, posted_by, search_vector, version, currency, deleted_at, deleted_by, sku, gtin FROM filtered f
+    WHERE $1::text = 'name'
+        AND ($2::uuid IS NULL
+            OR (f.name, f.id) > ($6::text, $2::uuid))
+    ORDER BY f.name, f.id
+    LIMIT $4)
+    UNION ALL
+    (SELECT id, name, price, created_at, updated_at, posted_by, search_vector, version, currency, deleted_at, deleted_by, sku, gtin FROM filtered f
+    WHERE $1::text = '-name'
+        AND ($2::uuid IS NULL
+            OR (f.name, f.id) < ($6::text, $2::uuid))
+    ORDER BY f.name DESC, f.id DESC
+    LIMIT $4)
+) page
+ORDER BY
+    CASE $1::text WHEN 'created_at' THEN page.created_at WHEN 'updated_at' THEN page.updated_at END,
+    CASE $1::text WHEN '-created_at' THEN page.created_at WHEN '-updated_at' THEN page.updated_at END DESC,
+    CASE $1::text WHEN 'price' THEN page.price END,
+    CASE $1::text WHEN '-price' THEN page.price END DESC,
+    CASE $1::text WHEN 'name' THEN page.name END,
+    CASE $1::text WHEN '-name' THEN page.name END DESC,
+    CASE WHEN $1::text LIKE '-%' THEN page.id END DESC,
+    page.id
+`
+
+type ListProductsParams struct {
+	Sort               string
+	CursorID           uuid.NullUUID
+	CursorTime         sql.NullTime
+	PageSize           int32
+	CursorPrice        sql.NullString
+	CursorName         sql.NullString
+	Name               sql.NullString
+	MinPrice           sql.NullString
+	MaxPrice           sql.NullString
+	PostedBy           uuid.NullUUID
+	CreatedAfter       sql.NullTime
+	CreatedBefore      sql.NullTime
+	UpdatedAfter       sql.NullTime
+	UpdatedBefore      sql.NullTime
+	CategoryID         uuid.NullUUID
+	IncludeDescendants bool
+	Tags               []string
+	MatchAllTags       bool
+}
+
+type ListProductsRow struct {
+	ID           uuid.UUID
+	Name         string
+	Price        string
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+	PostedBy     uuid.UUID
+	SearchVector sql.NullString
+	Version      int32
+	Currency     string
+	DeletedAt    sql.NullTime
+	DeletedBy    uuid.NullUUID
+	Sku          string
+	Gtin         sql.NullString
+}
+
+// ListProducts returns a page of products ordered by @sort, one of
+// created_at, updated_at, price or name with an optional - prefix for
+// descending order. Each ordering is a branch with a static ORDER BY so
+// Postgres walks the (column, id) index and stops after @page_size rows;
+// the branches not chosen by @sort are skipped, and the outer ORDER BY
+// only sorts the one page. The cursor value of the chosen sort is passed
+// in the argument of its column type.
+func (q *Queries) ListProducts(ctx context.Context, arg ListProductsParams) ([]ListProductsRow, error) {
+	rows, err := q.db.QueryContext(ctx, listProducts,
+		arg.Sort,
+		arg.CursorID,
+		arg.CursorTime,
+		arg.PageSize,
+		arg.CursorPrice,
+		arg.CursorName,
+		arg.Name,
+		arg.MinPrice,
+		arg.MaxPrice,
+		arg.PostedBy,
+		arg.CreatedAfter,
+		arg.CreatedBefore,
+		arg.UpdatedAfter,
+		arg.UpdatedBefore,
+		arg.CategoryID,
+		arg.IncludeDescendants,
+		pq.Array(arg.Tags),
+		arg.MatchAllTags,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListProductsRow
+	for rows.Next() {
+		var i ListProductsRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Price,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.PostedBy,
+			&i.SearchVector,
+			&i.Version,
+			&i.Currency,
+			&i.DeletedAt,
+			&i.DeletedBy,
+			&i.Sku,
+			&i.Gtin,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
@@ -7,32 +7,47 @@ package database
 
 import (
 	"context"
+	"database/sql"
+	"time"
 
 	"github.com/google/uuid"
 )
 
 const createProductsFromRequest = `-- name: CreateProductsFromRequest :one
-INSERT INTO products (id, name, price, created_at, updated_at, posted_by)
-VALUES (
-    gen_random_uuid(),
-    $1,
-    $2,
+INSERT INTO products (id, name, price, created_at, updated_at, posted_by, currency, sku, gtin)
+SELECT
+    new_product.id,
+    $1::text,
+    $2::numeric,
     NOW(),
     NOW(),
-    $3
-
-)
-RETURNING id, name, price, created_at, updated_at, posted_by
+    $3::uuid,
+    $4::text,
+    COALESCE($5::text, 'P-' || upper(replace(new_product.id::text, '-', ''))),
+    $6::text
+FROM (SELECT gen_random_uuid() AS id) new_product
+RETURNING id, name, price, created_at, updated_at, posted_by, search_vector, version, currency, deleted_at, deleted_by, sku, gtin
 `
 
 type CreateProductsFromRequestParams struct {
 	Name     string
 	Price    string
 	PostedBy uuid.UUID
+	Currency string
+	Sku      sql.NullString
+	Gtin     sql.NullString
 }
 
+// Without an SKU the product gets one derived from its id.
 func (q *Queries) CreateProductsFromRequest(ctx context.Context, arg CreateProductsFromRequestParams) (Product, error) {
-	row := q.db.QueryRowContext(ctx, createProductsFromRequest, arg.Name, arg.Price, arg.PostedBy)
+	row := q.db.QueryRowContext(ctx, createProductsFromRequest,
+		arg.Name,
+		arg.Price,
+		arg.PostedBy,
+		arg.Currency,
+		arg.Sku,
+		arg.Gtin,
+	)
 	var i Product
 	err := row.Scan(
 		&i.ID,
@@ -41,22 +56,20 @@ func (q *Queries) CreateProductsFromRequest(ctx context.Context, arg CreateProdu
 		&i.CreatedAt,
 		&i.UpdatedAt,
 		&i.PostedBy,
+		&i.SearchVector,
+		&i.Version,
+		&i.Currency,
+		&i.DeletedAt,
+		&i.DeletedBy,
+		&i.Sku,
+		&i.Gtin,
 	)
 	return i, err
 }
 
-const deleteProductByID = `-- name: DeleteProductByID :exec
-DELETE FROM products
-WHERE id = $1
-`
-
-func (q *Queries) DeleteProductByID(ctx context.Context, id uuid.UUID) error {
-	_, err := q.db.ExecContext(ctx, deleteProductByID, id)
-	return err
-}
-
 const getAllProducts = `-- name: GetAllProducts :many
-SELECT id, name, price, created_at, updated_at, posted_by FROM products
+SELECT id, name, price, created_at, updated_at, posted_by, search_vector, version, currency, deleted_at, deleted_by, sku, gtin FROM products
+WHERE deleted_at IS NULL
 `
 
 func (q *Queries) GetAllProducts(ctx context.Context) ([]Product, error) {
@@ -75,6 +88,13 @@ func (q *Queries) GetAllProducts(ctx context.Context) ([]Product, error) {
 			&i.CreatedAt,
 			&i.UpdatedAt,
 			&i.PostedBy,
+			&i.SearchVector,
+			&i.Version,
+			&i.Currency,
+			&i.DeletedAt,
+			&i.DeletedBy,
+			&i.Sku,
+			&i.Gtin,
 		); err != nil {
 			return nil, err
 		}
@@ -90,8 +110,9 @@ func (q *Queries) GetAllProducts(ctx context.Context) ([]Product, error) {
 }
 
 const getProductByID = `-- name: GetProductByID :one
-SELECT id, name, price, created_at, updated_at, posted_by FROM products
+SELECT id, name, price, created_at, updated_at, posted_by, search_vector, version, currency, deleted_at, deleted_by, sku, gtin FROM products
 WHERE id = $1
+    AND deleted_at IS NULL
 `
 
 func (q *Queries) GetProductByID(ctx context.Context, id uuid.UUID) (Product, error) {
@@ -104,28 +125,454 @@ func (q *Queries) GetProductByID(ctx context.Context, id uuid.UUID) (Product, er
 		&i.CreatedAt,
 		&i.UpdatedAt,
 		&i.PostedBy,
+		&i.SearchVector,
+		&i.Version,
+		&i.Currency,
+		&i.DeletedAt,
+		&i.DeletedBy,
+		&i.Sku,
+		&i.Gtin,
+	)
+	return i, err
+}
+
+const getProductBySKU = `-- name: GetProductBySKU :one
+SELECT id, name, price, created_at, updated_at, posted_by, search_vector, version, currency, deleted_at, deleted_by, sku, gtin FROM products
+WHERE id = (SELECT s.product_id FROM skus s WHERE s.sku = $1)
+    AND deleted_at IS NULL
+`
+
+// GetProductBySKU resolves product and variant SKUs alike, a variant SKU
+// to the product the variant belongs to.
+func (q *Queries) GetProductBySKU(ctx context.Context, sku string) (Product, error) {
+	row := q.db.QueryRowContext(ctx, getProductBySKU, sku)
+	var i Product
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Price,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.PostedBy,
+		&i.SearchVector,
+		&i.Version,
+		&i.Currency,
+		&i.DeletedAt,
+		&i.DeletedBy,
+		&i.Sku,
+		&i.Gtin,
+	)
+	return i, err
+}
+
+const getTrashedProductByID = `-- name: GetTrashedProductByID :one
+SELECT id, name, price, created_at, updated_at, posted_by, search_vector, version, currency, deleted_at, deleted_by, sku, gtin FROM products
+WHERE id = $1
+    AND deleted_at IS NOT NULL
+`
+
+func (q *Queries) GetTrashedProductByID(ctx context.Context, id uuid.UUID) (Product, error) {
+	row := q.db.QueryRowContext(ctx, getTrashedProductByID, id)
+	var i Product
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Price,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.PostedBy,
+		&i.SearchVector,
+		&i.Version,
+		&i.Currency,
+		&i.DeletedAt,
+		&i.DeletedBy,
+		&i.Sku,
+		&i.Gtin,
+	)
+	return i, err
+}
+
+const listTrashedProducts = `-- name: ListTrashedProducts :many
+SELECT id, name, price, created_at, updated_at, posted_by, search_vector, version, currency, deleted_at, deleted_by, sku, gtin FROM products
+WHERE deleted_at IS NOT NULL
+    AND ($1::uuid IS NULL OR posted_by = $1::uuid)
+ORDER BY deleted_at DESC, id
+LIMIT $3 OFFSET $2
+`
+
+type ListTrashedProductsParams struct {
+	PostedBy   uuid.NullUUID
+	PageOffset int32
+	PageSize   int32
+}
+
+func (q *Queries) ListTrashedProducts(ctx context.Context, arg ListTrashedProductsParams) ([]Product, error) {
+	rows, err := q.db.QueryContext(ctx, listTrashedProducts, arg.PostedBy, arg.PageOffset, arg.PageSize)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Product
+	for rows.Next() {
+		var i Product
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Price,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.PostedBy,
+			&i.SearchVector,
+			&i.Version,
+			&i.Currency,
+			&i.DeletedAt,
+			&i.DeletedBy,
+			&i.Sku,
+			&i.Gtin,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const patchProduct = `-- name: PatchProduct :one
+UPDATE products
+SET
+    name = COALESCE($1, name),
+    price = COALESCE($2, price),
+    currency = COALESCE($3, currency),
+    sku = COALESCE($4, sku),
+    gtin = CASE WHEN $5::bool THEN NULL ELSE COALESCE($6, gtin) END,
+    updated_at = NOW(),
+    version = version + 1
+WHERE id = $7
+    AND deleted_at IS NULL
+    AND ($8::int IS NULL OR version = $8::int)
+RETURNING id, name, price, created_at, updated_at, posted_by, search_vector, version, currency, deleted_at, deleted_by, sku, gtin
+`
+
+type PatchProductParams struct {
+	Name            sql.NullString
+	Price           sql.NullString
+	Currency        sql.NullString
+	Sku             sql.NullString
+	ClearGtin       bool
+	Gtin            sql.NullString
+	ID              uuid.UUID
+	ExpectedVersion sql.NullInt32
+}
+
+func (q *Queries) PatchProduct(ctx context.Context, arg PatchProductParams) (Product, error) {
+	row := q.db.QueryRowContext(ctx, patchProduct,
+		arg.Name,
+		arg.Price,
+		arg.Currency,
+		arg.Sku,
+		arg.ClearGtin,
+		arg.Gtin,
+		arg.ID,
+		arg.ExpectedVersion,
+	)
+	var i Product
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Price,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.PostedBy,
+		&i.SearchVector,
+		&i.Version,
+		&i.Currency,
+		&i.DeletedAt,
+		&i.DeletedBy,
+		&i.Sku,
+		&i.Gtin,
+	)
+	return i, err
+}
+
+const purgeProduct = `-- name: PurgeProduct :many
+WITH purged AS (
+    DELETE FROM products p
+    WHERE p.id = $1
+        AND p.deleted_at IS NOT NULL
+    RETURNING p.id
+)
+SELECT purged.id, i.storage_key, i.thumbnail_key
+FROM purged
+LEFT JOIN product_images i ON i.product_id = purged.id
+`
+
+type PurgeProductRow struct {
+	ID           uuid.UUID
+	StorageKey   sql.NullString
+	ThumbnailKey sql.NullString
+}
+
+// PurgeProduct returns a row per image file of the purged product, with
+// empty keys when it had none, and no rows when nothing was purged. The
+// image rows go with the product, the files are for the caller to delete.
+func (q *Queries) PurgeProduct(ctx context.Context, id uuid.UUID) ([]PurgeProductRow, error) {
+	rows, err := q.db.QueryContext(ctx, purgeProduct, id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []PurgeProductRow
+	for rows.Next() {
+		var i PurgeProductRow
+		if err := rows.Scan(&i.ID, &i.StorageKey, &i.ThumbnailKey); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const purgeTrashedProducts = `-- name: PurgeTrashedProducts :many
+WITH purged AS (
+    DELETE FROM products p
+    WHERE p.deleted_at < $1::timestamptz
+    RETURNING p.id
+)
+SELECT purged.id, i.storage_key, i.thumbnail_key
+FROM purged
+LEFT JOIN product_images i ON i.product_id = purged.id
+`
+
+type PurgeTrashedProductsRow struct {
+	ID           uuid.UUID
+	StorageKey   sql.NullString
+	ThumbnailKey sql.NullString
+}
+
+// PurgeTrashedProducts returns rows like PurgeProduct, for every product
+// purged.
+func (q *Queries) PurgeTrashedProducts(ctx context.Context, trashedBefore time.Time) ([]PurgeTrashedProductsRow, error) {
+	rows, err := q.db.QueryContext(ctx, purgeTrashedProducts, trashedBefore)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []PurgeTrashedProductsRow
+	for rows.Next() {
+		var i PurgeTrashedProductsRow
+		if err := rows.Scan(&i.ID, &i.StorageKey, &i.ThumbnailKey); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const restoreProduct = `-- name: RestoreProduct :one
+UPDATE products
+SET
+    deleted_at = NULL,
+    deleted_by = NULL,
+    updated_at = NOW(),
+    version = version + 1
+WHERE id = $1
+    AND deleted_at IS NOT NULL
+    AND ($2::int IS NULL OR version = $2::int)
+RETURNING id, name, price, created_at, updated_at, posted_by, search_vector, version, currency, deleted_at, deleted_by, sku, gtin
+`
+
+type RestoreProductParams struct {
+	ID              uuid.UUID
+	ExpectedVersion sql.NullInt32
+}
+
+func (q *Queries) RestoreProduct(ctx context.Context, arg RestoreProductParams) (Product, error) {
+	row := q.db.QueryRowContext(ctx, restoreProduct, arg.ID, arg.ExpectedVersion)
+	var i Product
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Price,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.PostedBy,
+		&i.SearchVector,
+		&i.Version,
+		&i.Currency,
+		&i.DeletedAt,
+		&i.DeletedBy,
+		&i.Sku,
+		&i.Gtin,
 	)
 	return i, err
 }
 
+const searchProducts = `-- name: SearchProducts :many
+SELECT
+    id,
+    name,
+    price,
+    created_at,
+    updated_at,
+    posted_by,
+    version,
+    currency,
+    sku,
+    gtin,
+    ts_rank(search_vector, to_tsquery('english', $1::text))::real AS rank,
+    -- the name is HTML-escaped before highlighting, so the <mark> tags are
+    -- the only markup in the snippet
+    ts_headline(
+        'english',
+        replace(replace(replace(replace(replace(name,
+            '&', '&amp;'), '<', '&lt;'), '>', '&gt;'), '"', '&#34;'), '''', '&#39;'),
+        to_tsquery('english', $1::text),
+        'StartSel=<mark>, StopSel=</mark>, HighlightAll=true'
+    )::text AS snippet
+FROM products
+WHERE search_vector @@ to_tsquery('english', $1::text)
+    AND deleted_at IS NULL
+ORDER BY rank DESC, id
+LIMIT $3 OFFSET $2
+`
+
+type SearchProductsParams struct {
+	Query      string
+	PageOffset int32
+	PageSize   int32
+}
+
+type SearchProductsRow struct {
+	ID        uuid.UUID
+	Name      string
+	Price     string
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	PostedBy  uuid.UUID
+	Version   int32
+	Currency  string
+	Sku       string
+	Gtin      sql.NullString
+	Rank      float32
+	Snippet   string
+}
+
+func (q *Queries) SearchProducts(ctx context.Context, arg SearchProductsParams) ([]SearchProductsRow, error) {
+	rows, err := q.db.QueryContext(ctx, searchProducts, arg.Query, arg.PageOffset, arg.PageSize)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []SearchProductsRow
+	for rows.Next() {
+		var i SearchProductsRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Price,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.PostedBy,
+			&i.Version,
+			&i.Currency,
+			&i.Sku,
+			&i.Gtin,
+			&i.Rank,
+			&i.Snippet,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const trashProductByID = `-- name: TrashProductByID :execrows
+UPDATE products
+SET
+    deleted_at = NOW(),
+    deleted_by = $1,
+    updated_at = NOW(),
+    version = version + 1
+WHERE id = $2
+    AND deleted_at IS NULL
+    AND ($3::int IS NULL OR version = $3::int)
+`
+
+type TrashProductByIDParams struct {
+	DeletedBy       uuid.NullUUID
+	ID              uuid.UUID
+	ExpectedVersion sql.NullInt32
+}
+
+func (q *Queries) TrashProductByID(ctx context.Context, arg TrashProductByIDParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, trashProductByID, arg.DeletedBy, arg.ID, arg.ExpectedVersion)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
 const updateProduct = `-- name: UpdateProduct :one
 UPDATE products
 SET 
-    name = $2,
-    price = $3,
-    updated_at = NOW()
-WHERE id = $1
-RETURNING id, name, price, created_at, updated_at, posted_by
+    name = $1,
+    price = $2,
+    currency = $3,
+    sku = COALESCE($4, sku),
+    gtin = COALESCE($5, gtin),
+    updated_at = NOW(),
+    version = version + 1
+WHERE id = $6
+    AND deleted_at IS NULL
+    AND ($7::int IS NULL OR version = $7::int)
+RETURNING id, name, price, created_at, updated_at, posted_by, search_vector, version, currency, deleted_at, deleted_by, sku, gtin
 `
 
 type UpdateProductParams struct {
-	ID    uuid.UUID
-	Name  string
-	Price string
+	Name            string
+	Price           string
+	Currency        string
+	Sku             sql.NullString
+	Gtin            sql.NullString
+	ID              uuid.UUID
+	ExpectedVersion sql.NullInt32
 }
 
 func (q *Queries) UpdateProduct(ctx context.Context, arg UpdateProductParams) (Product, error) {
-	row := q.db.QueryRowContext(ctx, updateProduct, arg.ID, arg.Name, arg.Price)
+	row := q.db.QueryRowContext(ctx, updateProduct,
+		arg.Name,
+		arg.Price,
+		arg.Currency,
+		arg.Sku,
+		arg.Gtin,
+		arg.ID,
+		arg.ExpectedVersion,
+	)
 	var i Product
 	err := row.Scan(
 		&i.ID,
@@ -134,6 +581,13 @@ func (q *Queries) UpdateProduct(ctx context.Context, arg UpdateProductParams) (P
 		&i.CreatedAt,
 		&i.UpdatedAt,
 		&i.PostedBy,
+		&i.SearchVector,
+		&i.Version,
+		&i.Currency,
+		&i.DeletedAt,
+		&i.DeletedBy,
+		&i.Sku,
+		&i.Gtin,
 	)
 	return i, err
 }
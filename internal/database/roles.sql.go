@@ -0,0 +1,88 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.30.0
+// source: roles.sql
+
+package database
+
+import (
+	"context"
+	"database/sql"
+)
+
+const getPermissionsByRole = `-- name: GetPermissionsByRole :many
+SELECT permission FROM role_permissions
+WHERE role = $1
+ORDER BY permission
+`
+
+func (q *Queries) GetPermissionsByRole(ctx context.Context, role string) ([]string, error) {
+	rows, err := q.db.QueryContext(ctx, getPermissionsByRole, role)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []string
+	for rows.Next() {
+		var permission string
+		if err := rows.Scan(&permission); err != nil {
+			return nil, err
+		}
+		items = append(items, permission)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getRoleByName = `-- name: GetRoleByName :one
+SELECT name, description, created_at FROM roles
+WHERE name = $1
+`
+
+func (q *Queries) GetRoleByName(ctx context.Context, name string) (Role, error) {
+	row := q.db.QueryRowContext(ctx, getRoleByName, name)
+	var i Role
+	err := row.Scan(&i.Name, &i.Description, &i.CreatedAt)
+	return i, err
+}
+
+const listRolePermissions = `-- name: ListRolePermissions :many
+SELECT roles.name, roles.description, role_permissions.permission
+FROM roles
+LEFT JOIN role_permissions ON role_permissions.role = roles.name
+ORDER BY roles.name, role_permissions.permission
+`
+
+type ListRolePermissionsRow struct {
+	Name        string
+	Description string
+	Permission  sql.NullString
+}
+
+func (q *Queries) ListRolePermissions(ctx context.Context) ([]ListRolePermissionsRow, error) {
+	rows, err := q.db.QueryContext(ctx, listRolePermissions)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListRolePermissionsRow
+	for rows.Next() {
+		var i ListRolePermissionsRow
+		if err := rows.Scan(&i.Name, &i.Description, &i.Permission); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
@@ -0,0 +1,135 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.30.0
+// source: tags.sql
+
+package database
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+const addProductTags = `-- name: AddProductTags :exec
+INSERT INTO product_tags (product_id, tag_id)
+SELECT $1, t.id
+FROM tags t
+WHERE t.name = ANY($2::text[])
+ON CONFLICT DO NOTHING
+`
+
+type AddProductTagsParams struct {
+	ProductID uuid.UUID
+	Names     []string
+}
+
+func (q *Queries) AddProductTags(ctx context.Context, arg AddProductTagsParams) error {
+	_, err := q.db.ExecContext(ctx, addProductTags, arg.ProductID, pq.Array(arg.Names))
+	return err
+}
+
+const clearProductTags = `-- name: ClearProductTags :exec
+DELETE FROM product_tags
+WHERE product_id = $1
+`
+
+func (q *Queries) ClearProductTags(ctx context.Context, productID uuid.UUID) error {
+	_, err := q.db.ExecContext(ctx, clearProductTags, productID)
+	return err
+}
+
+const ensureTags = `-- name: EnsureTags :exec
+INSERT INTO tags (name)
+SELECT unnest($1::text[])
+ON CONFLICT (name) DO NOTHING
+`
+
+func (q *Queries) EnsureTags(ctx context.Context, names []string) error {
+	_, err := q.db.ExecContext(ctx, ensureTags, pq.Array(names))
+	return err
+}
+
+const listTags = `-- name: ListTags :many
+SELECT t.name, COUNT(*) AS product_count
+FROM tags t
+JOIN product_tags pt ON pt.tag_id = t.id
+JOIN products p ON p.id = pt.product_id AND p.deleted_at IS NULL
+WHERE ($1::text IS NULL OR t.name LIKE $1::text || '%')
+GROUP BY t.id, t.name
+ORDER BY product_count DESC, t.name
+LIMIT $3 OFFSET $2
+`
+
+type ListTagsParams struct {
+	Prefix     sql.NullString
+	PageOffset int32
+	PageSize   int32
+}
+
+type ListTagsRow struct {
+	Name         string
+	ProductCount int64
+}
+
+// Tags in use on live products, most used first.
+func (q *Queries) ListTags(ctx context.Context, arg ListTagsParams) ([]ListTagsRow, error) {
+	rows, err := q.db.QueryContext(ctx, listTags, arg.Prefix, arg.PageOffset, arg.PageSize)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListTagsRow
+	for rows.Next() {
+		var i ListTagsRow
+		if err := rows.Scan(&i.Name, &i.ProductCount); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listTagsForProducts = `-- name: ListTagsForProducts :many
+SELECT pt.product_id, t.name
+FROM product_tags pt
+JOIN tags t ON t.id = pt.tag_id
+WHERE pt.product_id = ANY($1::uuid[])
+ORDER BY t.name
+`
+
+type ListTagsForProductsRow struct {
+	ProductID uuid.UUID
+	Name      string
+}
+
+func (q *Queries) ListTagsForProducts(ctx context.Context, productIds []uuid.UUID) ([]ListTagsForProductsRow, error) {
+	rows, err := q.db.QueryContext(ctx, listTagsForProducts, pq.Array(productIds))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListTagsForProductsRow
+	for rows.Next() {
+		var i ListTagsForProductsRow
+		if err := rows.Scan(&i.ProductID, &i.Name); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
@@ -7,10 +7,22 @@ package database
 
 import (
 	"context"
+	"database/sql"
 
 	"github.com/google/uuid"
 )
 
+const countUsers = `-- name: CountUsers :one
+SELECT COUNT(*) FROM users
+`
+
+func (q *Queries) CountUsers(ctx context.Context) (int64, error) {
+	row := q.db.QueryRowContext(ctx, countUsers)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
 const createUser = `-- name: CreateUser :one
 INSERT INTO users (id, email, hashedPassword, created_at, updated_at)
 VALUES (
@@ -21,7 +33,7 @@ VALUES (
     NOW()
 
 )
-RETURNING id, email, hashedpassword, created_at, updated_at, role
+RETURNING id, email, hashedpassword, created_at, updated_at, role, disabled_at
 `
 
 type CreateUserParams struct {
@@ -39,12 +51,26 @@ func (q *Queries) CreateUser(ctx context.Context, arg CreateUserParams) (User, e
 		&i.CreatedAt,
 		&i.UpdatedAt,
 		&i.Role,
+		&i.DisabledAt,
 	)
 	return i, err
 }
 
+const deleteUserByID = `-- name: DeleteUserByID :execrows
+DELETE FROM users
+WHERE id = $1
+`
+
+func (q *Queries) DeleteUserByID(ctx context.Context, id uuid.UUID) (int64, error) {
+	result, err := q.db.ExecContext(ctx, deleteUserByID, id)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
 const getRoleByID = `-- name: GetRoleByID :one
-SELECT role FROM users WHERE id = $1
+SELECT role FROM users WHERE id = $1 AND disabled_at IS NULL
 `
 
 func (q *Queries) GetRoleByID(ctx context.Context, id uuid.UUID) (string, error) {
@@ -55,7 +81,7 @@ func (q *Queries) GetRoleByID(ctx context.Context, id uuid.UUID) (string, error)
 }
 
 const getUserByEmail = `-- name: GetUserByEmail :one
-SELECT id, email, hashedpassword, created_at, updated_at, role FROM users 
+SELECT id, email, hashedpassword, created_at, updated_at, role, disabled_at FROM users 
 WHERE email = $1
 `
 
@@ -69,6 +95,127 @@ func (q *Queries) GetUserByEmail(ctx context.Context, email string) (User, error
 		&i.CreatedAt,
 		&i.UpdatedAt,
 		&i.Role,
+		&i.DisabledAt,
+	)
+	return i, err
+}
+
+const getUserByID = `-- name: GetUserByID :one
+SELECT id, email, hashedpassword, created_at, updated_at, role, disabled_at FROM users
+WHERE id = $1
+`
+
+func (q *Queries) GetUserByID(ctx context.Context, id uuid.UUID) (User, error) {
+	row := q.db.QueryRowContext(ctx, getUserByID, id)
+	var i User
+	err := row.Scan(
+		&i.ID,
+		&i.Email,
+		&i.Hashedpassword,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Role,
+		&i.DisabledAt,
+	)
+	return i, err
+}
+
+const listUsers = `-- name: ListUsers :many
+SELECT id, email, hashedpassword, created_at, updated_at, role, disabled_at FROM users
+ORDER BY created_at, id
+LIMIT $1 OFFSET $2
+`
+
+type ListUsersParams struct {
+	Limit  int32
+	Offset int32
+}
+
+func (q *Queries) ListUsers(ctx context.Context, arg ListUsersParams) ([]User, error) {
+	rows, err := q.db.QueryContext(ctx, listUsers, arg.Limit, arg.Offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []User
+	for rows.Next() {
+		var i User
+		if err := rows.Scan(
+			&i.ID,
+			&i.Email,
+			&i.Hashedpassword,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Role,
+			&i.DisabledAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const setUserDisabledAt = `-- name: SetUserDisabledAt :one
+UPDATE users
+SET
+    disabled_at = $2,
+    updated_at = NOW()
+WHERE id = $1
+RETURNING id, email, hashedpassword, created_at, updated_at, role, disabled_at
+`
+
+type SetUserDisabledAtParams struct {
+	ID         uuid.UUID
+	DisabledAt sql.NullTime
+}
+
+func (q *Queries) SetUserDisabledAt(ctx context.Context, arg SetUserDisabledAtParams) (User, error) {
+	row := q.db.QueryRowContext(ctx, setUserDisabledAt, arg.ID, arg.DisabledAt)
+	var i User
+	err := row.Scan(
+		&i.ID,
+		&i.Email,
+		&i.Hashedpassword,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Role,
+		&i.DisabledAt,
+	)
+	return i, err
+}
+
+const updateUserRole = `-- name: UpdateUserRole :one
+UPDATE users
+SET
+    role = $2,
+    updated_at = NOW()
+WHERE id = $1
+RETURNING id, email, hashedpassword, created_at, updated_at, role, disabled_at
+`
+
+type UpdateUserRoleParams struct {
+	ID   uuid.UUID
+	Role string
+}
+
+func (q *Queries) UpdateUserRole(ctx context.Context, arg UpdateUserRoleParams) (User, error) {
+	row := q.db.QueryRowContext(ctx, updateUserRole, arg.ID, arg.Role)
+	var i User
+	err := row.Scan(
+		&i.ID,
+		&i.Email,
+		&i.Hashedpassword,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Role,
+		&i.DisabledAt,
 	)
 	return i, err
 }
@@ -0,0 +1,306 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.30.0
+// source: variants.sql
+
+package database
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+const createProductVariant = `-- name: CreateProductVariant :one
+INSERT INTO product_variants (product_id, sku, options, price, currency)
+VALUES ($1, $2, $3, $4, $5)
+RETURNING id, product_id, sku, options, price, currency, created_at, updated_at
+`
+
+type CreateProductVariantParams struct {
+	ProductID uuid.UUID
+	Sku       string
+	Options   json.RawMessage
+	Price     sql.NullString
+	Currency  sql.NullString
+}
+
+func (q *Queries) CreateProductVariant(ctx context.Context, arg CreateProductVariantParams) (ProductVariant, error) {
+	row := q.db.QueryRowContext(ctx, createProductVariant,
+		arg.ProductID,
+		arg.Sku,
+		arg.Options,
+		arg.Price,
+		arg.Currency,
+	)
+	var i ProductVariant
+	err := row.Scan(
+		&i.ID,
+		&i.ProductID,
+		&i.Sku,
+		&i.Options,
+		&i.Price,
+		&i.Currency,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const deleteProductVariant = `-- name: DeleteProductVariant :execrows
+DELETE FROM product_variants
+WHERE id = $1 AND product_id = $2
+`
+
+type DeleteProductVariantParams struct {
+	ID        uuid.UUID
+	ProductID uuid.UUID
+}
+
+func (q *Queries) DeleteProductVariant(ctx context.Context, arg DeleteProductVariantParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, deleteProductVariant, arg.ID, arg.ProductID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const getOtherVariantOptions = `-- name: GetOtherVariantOptions :one
+SELECT options FROM product_variants
+WHERE product_id = $1 AND id <> $2
+LIMIT 1
+`
+
+type GetOtherVariantOptionsParams struct {
+	ProductID uuid.UUID
+	ExcludeID uuid.UUID
+}
+
+func (q *Queries) GetOtherVariantOptions(ctx context.Context, arg GetOtherVariantOptionsParams) (json.RawMessage, error) {
+	row := q.db.QueryRowContext(ctx, getOtherVariantOptions, arg.ProductID, arg.ExcludeID)
+	var options json.RawMessage
+	err := row.Scan(&options)
+	return options, err
+}
+
+const getVariantOnHand = `-- name: GetVariantOnHand :one
+SELECT COALESCE(SUM(on_hand), 0)::bigint AS on_hand
+FROM stock_levels
+WHERE variant_id = $1
+`
+
+func (q *Queries) GetVariantOnHand(ctx context.Context, variantID uuid.NullUUID) (int64, error) {
+	row := q.db.QueryRowContext(ctx, getVariantOnHand, variantID)
+	var on_hand int64
+	err := row.Scan(&on_hand)
+	return on_hand, err
+}
+
+const listVariantsForProducts = `-- name: ListVariantsForProducts :many
+SELECT
+    v.id,
+    v.product_id,
+    v.sku,
+    v.options,
+    COALESCE(v.price, p.price)::text AS price,
+    COALESCE(v.currency, p.currency)::text AS currency,
+    (v.price IS NOT NULL)::boolean AS price_override,
+    COALESCE(s.on_hand, 0)::bigint AS on_hand,
+    v.created_at,
+    v.updated_at
+FROM product_variants v
+JOIN products p ON p.id = v.product_id
+LEFT JOIN (
+    SELECT variant_id, SUM(on_hand) AS on_hand
+    FROM stock_levels
+    WHERE variant_id IS NOT NULL
+    GROUP BY variant_id
+) s ON s.variant_id = v.id
+WHERE v.product_id = ANY($1::uuid[])
+ORDER BY v.product_id, v.created_at, v.id
+`
+
+type ListVariantsForProductsRow struct {
+	ID            uuid.UUID
+	ProductID     uuid.UUID
+	Sku           string
+	Options       json.RawMessage
+	Price         string
+	Currency      string
+	PriceOverride bool
+	OnHand        int64
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
+}
+
+func (q *Queries) ListVariantsForProducts(ctx context.Context, productIds []uuid.UUID) ([]ListVariantsForProductsRow, error) {
+	rows, err := q.db.QueryContext(ctx, listVariantsForProducts, pq.Array(productIds))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListVariantsForProductsRow
+	for rows.Next() {
+		var i ListVariantsForProductsRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.ProductID,
+			&i.Sku,
+			&i.Options,
+			&i.Price,
+			&i.Currency,
+			&i.PriceOverride,
+			&i.OnHand,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const lockProductVariant = `-- name: LockProductVariant :one
+SELECT id, product_id, sku, options, price, currency, created_at, updated_at FROM product_variants
+WHERE id = $1 AND product_id = $2
+FOR UPDATE
+`
+
+type LockProductVariantParams struct {
+	ID        uuid.UUID
+	ProductID uuid.UUID
+}
+
+func (q *Queries) LockProductVariant(ctx context.Context, arg LockProductVariantParams) (ProductVariant, error) {
+	row := q.db.QueryRowContext(ctx, lockProductVariant, arg.ID, arg.ProductID)
+	var i ProductVariant
+	err := row.Scan(
+		&i.ID,
+		&i.ProductID,
+		&i.Sku,
+		&i.Options,
+		&i.Price,
+		&i.Currency,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const shareLockProductVariant = `-- name: ShareLockProductVariant :one
+SELECT id, product_id, sku, options, price, currency, created_at, updated_at FROM product_variants
+WHERE id = $1 AND product_id = $2
+FOR SHARE
+`
+
+type ShareLockProductVariantParams struct {
+	ID        uuid.UUID
+	ProductID uuid.UUID
+}
+
+// Stock movements of a variant hold this lock, so the variant cannot be
+// deleted while its stock moves.
+func (q *Queries) ShareLockProductVariant(ctx context.Context, arg ShareLockProductVariantParams) (ProductVariant, error) {
+	row := q.db.QueryRowContext(ctx, shareLockProductVariant, arg.ID, arg.ProductID)
+	var i ProductVariant
+	err := row.Scan(
+		&i.ID,
+		&i.ProductID,
+		&i.Sku,
+		&i.Options,
+		&i.Price,
+		&i.Currency,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const touchProduct = `-- name: TouchProduct :one
+UPDATE products
+SET
+    updated_at = NOW(),
+    version = version + 1
+WHERE id = $1
+    AND deleted_at IS NULL
+RETURNING id, name, price, created_at, updated_at, posted_by, search_vector, version, currency, deleted_at, deleted_by, sku, gtin
+`
+
+// TouchProduct bumps the version of a product whose variants changed, so
+// its ETag changes with them. It also locks the product row for the rest of
+// the transaction.
+func (q *Queries) TouchProduct(ctx context.Context, id uuid.UUID) (Product, error) {
+	row := q.db.QueryRowContext(ctx, touchProduct, id)
+	var i Product
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Price,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.PostedBy,
+		&i.SearchVector,
+		&i.Version,
+		&i.Currency,
+		&i.DeletedAt,
+		&i.DeletedBy,
+		&i.Sku,
+		&i.Gtin,
+	)
+	return i, err
+}
+
+const updateProductVariant = `-- name: UpdateProductVariant :one
+UPDATE product_variants
+SET
+    sku = $1,
+    options = $2,
+    price = $3,
+    currency = $4,
+    updated_at = NOW()
+WHERE id = $5 AND product_id = $6
+RETURNING id, product_id, sku, options, price, currency, created_at, updated_at
+`
+
+type UpdateProductVariantParams struct {
+	Sku       string
+	Options   json.RawMessage
+	Price     sql.NullString
+	Currency  sql.NullString
+	ID        uuid.UUID
+	ProductID uuid.UUID
+}
+
+func (q *Queries) UpdateProductVariant(ctx context.Context, arg UpdateProductVariantParams) (ProductVariant, error) {
+	row := q.db.QueryRowContext(ctx, updateProductVariant,
+		arg.Sku,
+		arg.Options,
+		arg.Price,
+		arg.Currency,
+		arg.ID,
+		arg.ProductID,
+	)
+	var i ProductVariant
+	err := row.Scan(
+		&i.ID,
+		&i.ProductID,
+		&i.Sku,
+		&i.Options,
+		&i.Price,
+		&i.Currency,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
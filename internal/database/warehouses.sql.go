@@ -0,0 +1,174 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.30.0
+// source: warehouses.sql
+
+package database
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+const clearDefaultWarehouse = `-- name: ClearDefaultWarehouse :exec
+UPDATE warehouses
+SET
+    is_default = false,
+    updated_at = NOW()
+WHERE is_default AND id <> $1
+`
+
+func (q *Queries) ClearDefaultWarehouse(ctx context.Context, keepID uuid.UUID) error {
+	_, err := q.db.ExecContext(ctx, clearDefaultWarehouse, keepID)
+	return err
+}
+
+const createWarehouse = `-- name: CreateWarehouse :one
+INSERT INTO warehouses (code, name, is_default)
+VALUES ($1, $2, $3)
+RETURNING id, code, name, is_default, created_at, updated_at
+`
+
+type CreateWarehouseParams struct {
+	Code      string
+	Name      string
+	IsDefault bool
+}
+
+func (q *Queries) CreateWarehouse(ctx context.Context, arg CreateWarehouseParams) (Warehouse, error) {
+	row := q.db.QueryRowContext(ctx, createWarehouse, arg.Code, arg.Name, arg.IsDefault)
+	var i Warehouse
+	err := row.Scan(
+		&i.ID,
+		&i.Code,
+		&i.Name,
+		&i.IsDefault,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const deleteWarehouse = `-- name: DeleteWarehouse :execrows
+DELETE FROM warehouses
+WHERE id = $1 AND NOT is_default
+`
+
+func (q *Queries) DeleteWarehouse(ctx context.Context, id uuid.UUID) (int64, error) {
+	result, err := q.db.ExecContext(ctx, deleteWarehouse, id)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const getDefaultWarehouse = `-- name: GetDefaultWarehouse :one
+SELECT id, code, name, is_default, created_at, updated_at FROM warehouses
+WHERE is_default
+`
+
+func (q *Queries) GetDefaultWarehouse(ctx context.Context) (Warehouse, error) {
+	row := q.db.QueryRowContext(ctx, getDefaultWarehouse)
+	var i Warehouse
+	err := row.Scan(
+		&i.ID,
+		&i.Code,
+		&i.Name,
+		&i.IsDefault,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const getWarehouseByID = `-- name: GetWarehouseByID :one
+SELECT id, code, name, is_default, created_at, updated_at FROM warehouses
+WHERE id = $1
+`
+
+func (q *Queries) GetWarehouseByID(ctx context.Context, id uuid.UUID) (Warehouse, error) {
+	row := q.db.QueryRowContext(ctx, getWarehouseByID, id)
+	var i Warehouse
+	err := row.Scan(
+		&i.ID,
+		&i.Code,
+		&i.Name,
+		&i.IsDefault,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const listWarehouses = `-- name: ListWarehouses :many
+SELECT id, code, name, is_default, created_at, updated_at FROM warehouses
+ORDER BY code
+`
+
+func (q *Queries) ListWarehouses(ctx context.Context) ([]Warehouse, error) {
+	rows, err := q.db.QueryContext(ctx, listWarehouses)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Warehouse
+	for rows.Next() {
+		var i Warehouse
+		if err := rows.Scan(
+			&i.ID,
+			&i.Code,
+			&i.Name,
+			&i.IsDefault,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const updateWarehouse = `-- name: UpdateWarehouse :one
+UPDATE warehouses
+SET
+    code = $1,
+    name = $2,
+    is_default = $3,
+    updated_at = NOW()
+WHERE id = $4
+RETURNING id, code, name, is_default, created_at, updated_at
+`
+
+type UpdateWarehouseParams struct {
+	Code      string
+	Name      string
+	IsDefault bool
+	ID        uuid.UUID
+}
+
+func (q *Queries) UpdateWarehouse(ctx context.Context, arg UpdateWarehouseParams) (Warehouse, error) {
+	row := q.db.QueryRowContext(ctx, updateWarehouse,
+		arg.Code,
+		arg.Name,
+		arg.IsDefault,
+		arg.ID,
+	)
+	var i Warehouse
+	err := row.Scan(
+		&i.ID,
+		&i.Code,
+		&i.Name,
+		&i.IsDefault,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
@@ -0,0 +1,30 @@
+// Package db holds the goose migrations and the sqlc queries.
+package db
+
+import (
+	"embed"
+	"io/fs"
+	"strconv"
+	"strings"
+)
+
+//go:embed migrations/*.sql
+var migrations embed.FS
+
+// Migrations returns the versions of the migrations this build ships
+// with, oldest first.
+func Migrations() []int64 {
+	entries, err := fs.ReadDir(migrations, "migrations")
+	if err != nil {
+		panic(err)
+	}
+	var versions []int64
+	for _, entry := range entries {
+		prefix, _, _ := strings.Cut(entry.Name(), "_")
+		if version, err := strconv.ParseInt(prefix, 10, 64); err == nil {
+			versions = append(versions, version)
+		}
+	}
+	// ReadDir sorts by name, and the prefixes all have the same width
+	return versions
+}
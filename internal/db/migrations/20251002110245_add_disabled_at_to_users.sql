@@ -0,0 +1,7 @@
+-- +goose Up
+ALTER TABLE users
+    ADD COLUMN disabled_at TIMESTAMP;
+
+-- +goose Down
+ALTER TABLE users
+    DROP COLUMN disabled_at;
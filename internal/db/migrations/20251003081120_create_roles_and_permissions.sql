@@ -0,0 +1,47 @@
+-- +goose Up
+CREATE TABLE roles (
+    name TEXT PRIMARY KEY,
+    description TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE role_permissions (
+    role TEXT NOT NULL REFERENCES roles(name) ON UPDATE CASCADE ON DELETE CASCADE,
+    permission TEXT NOT NULL,
+    PRIMARY KEY (role, permission)
+);
+
+INSERT INTO roles (name, description) VALUES
+    ('admin', 'full access, including user management'),
+    ('editor', 'can create products and edit any product'),
+    ('user', 'can create products and manage their own'),
+    ('viewer', 'read only access');
+
+INSERT INTO roles (name)
+SELECT DISTINCT role FROM users
+ON CONFLICT (name) DO NOTHING;
+
+INSERT INTO role_permissions (role, permission) VALUES
+    ('admin', 'product:create'),
+    ('admin', 'product:update:own'),
+    ('admin', 'product:update:any'),
+    ('admin', 'product:delete:own'),
+    ('admin', 'product:delete:any'),
+    ('admin', 'user:manage'),
+    ('editor', 'product:create'),
+    ('editor', 'product:update:own'),
+    ('editor', 'product:update:any'),
+    ('editor', 'product:delete:own'),
+    ('user', 'product:create'),
+    ('user', 'product:update:own'),
+    ('user', 'product:delete:own');
+
+ALTER TABLE users
+    ADD CONSTRAINT users_role_fkey FOREIGN KEY (role) REFERENCES roles(name) ON UPDATE CASCADE;
+
+-- +goose Down
+ALTER TABLE users
+    DROP CONSTRAINT IF EXISTS users_role_fkey;
+
+DROP TABLE IF EXISTS role_permissions;
+DROP TABLE IF EXISTS roles;
@@ -0,0 +1,11 @@
+-- +goose Up
+CREATE INDEX idx_products_created_at_id ON products (created_at, id);
+CREATE INDEX idx_products_updated_at_id ON products (updated_at, id);
+CREATE INDEX idx_products_price_id ON products (price, id);
+CREATE INDEX idx_products_posted_by ON products (posted_by);
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_products_posted_by;
+DROP INDEX IF EXISTS idx_products_price_id;
+DROP INDEX IF EXISTS idx_products_updated_at_id;
+DROP INDEX IF EXISTS idx_products_created_at_id;
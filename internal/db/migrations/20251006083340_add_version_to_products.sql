@@ -0,0 +1,7 @@
+-- +goose Up
+ALTER TABLE products
+    ADD COLUMN version INTEGER NOT NULL DEFAULT 1;
+
+-- +goose Down
+ALTER TABLE products
+    DROP COLUMN version;
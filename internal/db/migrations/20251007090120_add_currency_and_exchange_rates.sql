@@ -0,0 +1,24 @@
+-- +goose Up
+ALTER TABLE products
+    ADD COLUMN currency TEXT NOT NULL DEFAULT 'USD' CHECK (currency ~ '^[A-Z]{3}$');
+
+CREATE TABLE exchange_rates (
+    base_currency TEXT NOT NULL CHECK (base_currency ~ '^[A-Z]{3}$'),
+    quote_currency TEXT NOT NULL CHECK (quote_currency ~ '^[A-Z]{3}$'),
+    rate NUMERIC(20,10) NOT NULL CHECK (rate > 0),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    PRIMARY KEY (base_currency, quote_currency),
+    CHECK (base_currency <> quote_currency)
+);
+
+INSERT INTO role_permissions (role, permission) VALUES
+    ('admin', 'currency:manage');
+
+-- +goose Down
+DELETE FROM role_permissions WHERE permission = 'currency:manage';
+
+DROP TABLE IF EXISTS exchange_rates;
+
+ALTER TABLE products
+    DROP COLUMN currency;
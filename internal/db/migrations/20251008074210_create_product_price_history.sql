@@ -0,0 +1,20 @@
+-- +goose Up
+CREATE TABLE product_price_history (
+    id BIGSERIAL PRIMARY KEY,
+    product_id UUID NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+    price NUMERIC(10,2) NOT NULL,
+    currency TEXT NOT NULL,
+    changed_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    changed_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX product_price_history_product_changed_at_idx
+    ON product_price_history (product_id, changed_at DESC, id DESC);
+
+-- who set the current prices is unknown, and updated_at is the earliest
+-- time they are known to have been in effect
+INSERT INTO product_price_history (product_id, price, currency, changed_at)
+SELECT id, price, currency, updated_at FROM products;
+
+-- +goose Down
+DROP TABLE IF EXISTS product_price_history;
@@ -0,0 +1,30 @@
+-- +goose Up
+ALTER TABLE products
+    ADD COLUMN deleted_at TIMESTAMPTZ,
+    ADD COLUMN deleted_by UUID REFERENCES users(id) ON DELETE SET NULL;
+
+CREATE INDEX products_deleted_at_idx ON products (deleted_at) WHERE deleted_at IS NOT NULL;
+
+-- deleting a user must no longer take their products with it
+ALTER TABLE products
+    DROP CONSTRAINT products_posted_by_fkey,
+    ADD CONSTRAINT products_posted_by_fkey FOREIGN KEY (posted_by) REFERENCES users(id) ON DELETE RESTRICT;
+
+INSERT INTO role_permissions (role, permission) VALUES
+    ('admin', 'product:purge');
+
+-- +goose Down
+DELETE FROM role_permissions WHERE permission = 'product:purge';
+
+ALTER TABLE products
+    DROP CONSTRAINT products_posted_by_fkey,
+    ADD CONSTRAINT products_posted_by_fkey FOREIGN KEY (posted_by) REFERENCES users(id) ON DELETE CASCADE;
+
+-- trashed products would come back to life without the column
+DELETE FROM products WHERE deleted_at IS NOT NULL;
+
+DROP INDEX IF EXISTS products_deleted_at_idx;
+
+ALTER TABLE products
+    DROP COLUMN deleted_by,
+    DROP COLUMN deleted_at;
@@ -0,0 +1,32 @@
+-- +goose Up
+CREATE TABLE categories (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    parent_id UUID REFERENCES categories(id) ON DELETE RESTRICT,
+    name TEXT NOT NULL,
+    slug TEXT NOT NULL CHECK (slug ~ '^[a-z0-9]+(-[a-z0-9]+)*$'),
+    -- slugs of the ancestors and the category itself joined by "/", kept up
+    -- to date when a category is renamed or moved
+    path TEXT NOT NULL UNIQUE,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX categories_parent_id_idx ON categories (parent_id);
+CREATE INDEX categories_path_pattern_idx ON categories (path text_pattern_ops);
+
+CREATE TABLE product_categories (
+    product_id UUID NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+    category_id UUID NOT NULL REFERENCES categories(id) ON DELETE CASCADE,
+    PRIMARY KEY (product_id, category_id)
+);
+
+CREATE INDEX product_categories_category_id_idx ON product_categories (category_id);
+
+INSERT INTO role_permissions (role, permission) VALUES
+    ('admin', 'category:manage');
+
+-- +goose Down
+DELETE FROM role_permissions WHERE permission = 'category:manage';
+
+DROP TABLE IF EXISTS product_categories;
+DROP TABLE IF EXISTS categories;
@@ -0,0 +1,18 @@
+-- +goose Up
+CREATE TABLE tags (
+    id BIGSERIAL PRIMARY KEY,
+    name TEXT NOT NULL UNIQUE CHECK (name <> '' AND name = lower(name)),
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE product_tags (
+    product_id UUID NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+    tag_id BIGINT NOT NULL REFERENCES tags(id) ON DELETE CASCADE,
+    PRIMARY KEY (product_id, tag_id)
+);
+
+CREATE INDEX product_tags_tag_id_idx ON product_tags (tag_id);
+
+-- +goose Down
+DROP TABLE IF EXISTS product_tags;
+DROP TABLE IF EXISTS tags;
@@ -0,0 +1,21 @@
+-- +goose Up
+ALTER TABLE products
+    ADD COLUMN sku TEXT,
+    ADD COLUMN gtin TEXT;
+
+-- existing products get an SKU derived from their id, the same one the API
+-- assigns to new products created without an SKU
+UPDATE products SET sku = 'P-' || upper(replace(id::text, '-', ''));
+
+ALTER TABLE products
+    ALTER COLUMN sku SET NOT NULL,
+    ADD CONSTRAINT products_sku_check CHECK (sku ~ '^[A-Z0-9][A-Z0-9._-]{0,63}$'),
+    ADD CONSTRAINT products_sku_key UNIQUE (sku),
+    -- GTINs are stored as 13 digits, UPC-A codes with a leading zero
+    ADD CONSTRAINT products_gtin_check CHECK (gtin ~ '^[0-9]{13}$'),
+    ADD CONSTRAINT products_gtin_key UNIQUE (gtin);
+
+-- +goose Down
+ALTER TABLE products
+    DROP COLUMN gtin,
+    DROP COLUMN sku;
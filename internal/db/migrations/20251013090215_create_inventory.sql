@@ -0,0 +1,32 @@
+-- +goose Up
+-- stock_movements is the ledger, stock_levels the running total derived from
+-- it. Both are written in one transaction that holds the stock_levels row
+-- lock, which is what keeps on_hand from going negative.
+CREATE TABLE stock_levels (
+    product_id UUID PRIMARY KEY REFERENCES products(id) ON DELETE CASCADE,
+    on_hand INTEGER NOT NULL DEFAULT 0 CHECK (on_hand >= 0),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE stock_movements (
+    id BIGSERIAL PRIMARY KEY,
+    product_id UUID NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+    kind TEXT NOT NULL CHECK (kind IN ('receive', 'sell', 'adjust', 'return')),
+    quantity INTEGER NOT NULL CHECK (quantity <> 0),
+    reason TEXT NOT NULL DEFAULT '',
+    actor_id UUID REFERENCES users(id) ON DELETE SET NULL,
+    balance_after INTEGER NOT NULL CHECK (balance_after >= 0),
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX stock_movements_product_id_idx ON stock_movements (product_id, id DESC);
+
+INSERT INTO role_permissions (role, permission) VALUES
+    ('admin', 'inventory:manage'),
+    ('editor', 'inventory:manage');
+
+-- +goose Down
+DELETE FROM role_permissions WHERE permission = 'inventory:manage';
+
+DROP TABLE IF EXISTS stock_movements;
+DROP TABLE IF EXISTS stock_levels;
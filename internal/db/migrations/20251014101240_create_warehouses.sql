@@ -0,0 +1,61 @@
+-- +goose Up
+CREATE TABLE warehouses (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    code TEXT NOT NULL UNIQUE,
+    name TEXT NOT NULL,
+    is_default BOOLEAN NOT NULL DEFAULT false,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+-- Movements that do not name a warehouse go to the default one.
+CREATE UNIQUE INDEX warehouses_default_idx ON warehouses (is_default) WHERE is_default;
+
+INSERT INTO warehouses (code, name, is_default) VALUES ('MAIN', 'Main warehouse', true);
+
+ALTER TABLE stock_levels ADD COLUMN warehouse_id UUID REFERENCES warehouses(id) ON DELETE RESTRICT;
+UPDATE stock_levels SET warehouse_id = (SELECT id FROM warehouses WHERE is_default);
+ALTER TABLE stock_levels ALTER COLUMN warehouse_id SET NOT NULL;
+ALTER TABLE stock_levels DROP CONSTRAINT stock_levels_pkey;
+ALTER TABLE stock_levels ADD PRIMARY KEY (product_id, warehouse_id);
+
+-- A transfer is two movements of kind transfer sharing a transfer_id, one
+-- taking the stock out of the source warehouse and one putting it into the
+-- destination.
+ALTER TABLE stock_movements ADD COLUMN warehouse_id UUID REFERENCES warehouses(id) ON DELETE RESTRICT;
+ALTER TABLE stock_movements ADD COLUMN transfer_id UUID;
+UPDATE stock_movements SET warehouse_id = (SELECT id FROM warehouses WHERE is_default);
+ALTER TABLE stock_movements ALTER COLUMN warehouse_id SET NOT NULL;
+ALTER TABLE stock_movements DROP CONSTRAINT stock_movements_kind_check;
+ALTER TABLE stock_movements ADD CONSTRAINT stock_movements_kind_check
+    CHECK (kind IN ('receive', 'sell', 'adjust', 'return', 'transfer'));
+ALTER TABLE stock_movements ADD CONSTRAINT stock_movements_transfer_check
+    CHECK ((kind = 'transfer') = (transfer_id IS NOT NULL));
+
+CREATE INDEX stock_movements_transfer_id_idx ON stock_movements (transfer_id) WHERE transfer_id IS NOT NULL;
+
+INSERT INTO role_permissions (role, permission) VALUES
+    ('admin', 'warehouse:manage');
+
+-- +goose Down
+DELETE FROM role_permissions WHERE permission = 'warehouse:manage';
+
+-- Only the stock of the default warehouse survives the rollback.
+DELETE FROM stock_movements
+WHERE warehouse_id <> (SELECT id FROM warehouses WHERE is_default)
+    OR kind = 'transfer';
+DELETE FROM stock_levels WHERE warehouse_id <> (SELECT id FROM warehouses WHERE is_default);
+
+DROP INDEX IF EXISTS stock_movements_transfer_id_idx;
+ALTER TABLE stock_movements DROP CONSTRAINT stock_movements_transfer_check;
+ALTER TABLE stock_movements DROP CONSTRAINT stock_movements_kind_check;
+ALTER TABLE stock_movements ADD CONSTRAINT stock_movements_kind_check
+    CHECK (kind IN ('receive', 'sell', 'adjust', 'return'));
+ALTER TABLE stock_movements DROP COLUMN transfer_id;
+ALTER TABLE stock_movements DROP COLUMN warehouse_id;
+
+ALTER TABLE stock_levels DROP CONSTRAINT stock_levels_pkey;
+ALTER TABLE stock_levels ADD PRIMARY KEY (product_id);
+ALTER TABLE stock_levels DROP COLUMN warehouse_id;
+
+DROP TABLE IF EXISTS warehouses;
@@ -0,0 +1,38 @@
+-- +goose Up
+-- options holds the option values that tell the variants of a product apart,
+-- e.g. {"size": "M", "colour": "red"}. price and currency override the price
+-- of the product when set.
+CREATE TABLE product_variants (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    product_id UUID NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+    sku TEXT NOT NULL,
+    options JSONB NOT NULL CHECK (jsonb_typeof(options) = 'object'),
+    price NUMERIC(10,2) CHECK (price >= 0),
+    currency TEXT CHECK (currency ~ '^[A-Z]{3}$'),
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    CONSTRAINT product_variants_sku_key UNIQUE (sku),
+    CONSTRAINT product_variants_options_key UNIQUE (product_id, options),
+    CHECK ((price IS NULL) = (currency IS NULL))
+);
+
+-- Stock without a variant is stock of the product itself.
+ALTER TABLE stock_levels ADD COLUMN variant_id UUID REFERENCES product_variants(id) ON DELETE CASCADE;
+ALTER TABLE stock_levels DROP CONSTRAINT stock_levels_pkey;
+CREATE UNIQUE INDEX stock_levels_key ON stock_levels
+    (product_id, warehouse_id, COALESCE(variant_id, '00000000-0000-0000-0000-000000000000'));
+CREATE INDEX stock_levels_variant_id_idx ON stock_levels (variant_id) WHERE variant_id IS NOT NULL;
+
+ALTER TABLE stock_movements ADD COLUMN variant_id UUID REFERENCES product_variants(id) ON DELETE CASCADE;
+
+-- +goose Down
+DELETE FROM stock_movements WHERE variant_id IS NOT NULL;
+ALTER TABLE stock_movements DROP COLUMN variant_id;
+
+DELETE FROM stock_levels WHERE variant_id IS NOT NULL;
+DROP INDEX IF EXISTS stock_levels_variant_id_idx;
+DROP INDEX IF EXISTS stock_levels_key;
+ALTER TABLE stock_levels DROP COLUMN variant_id;
+ALTER TABLE stock_levels ADD PRIMARY KEY (product_id, warehouse_id);
+
+DROP TABLE IF EXISTS product_variants;
@@ -0,0 +1,20 @@
+-- +goose Up
+-- The files themselves live in the configured storage under storage_key and
+-- thumbnail_key. Images are shown in position order.
+CREATE TABLE product_images (
+    id UUID PRIMARY KEY,
+    product_id UUID NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+    position INTEGER NOT NULL,
+    storage_key TEXT NOT NULL,
+    thumbnail_key TEXT NOT NULL,
+    content_type TEXT NOT NULL,
+    size_bytes BIGINT NOT NULL,
+    width INTEGER NOT NULL,
+    height INTEGER NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX product_images_product_id_idx ON product_images (product_id, position);
+
+-- +goose Down
+DROP TABLE IF EXISTS product_images;
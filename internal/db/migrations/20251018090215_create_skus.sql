@@ -0,0 +1,62 @@
+-- +goose Up
+-- skus holds the SKU of every product and variant, so the two share one
+-- namespace. A variant row has variant_id set, a product row has it NULL.
+-- The triggers below keep it in step with products and product_variants.
+CREATE TABLE skus (
+    sku TEXT NOT NULL,
+    product_id UUID NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+    variant_id UUID REFERENCES product_variants(id) ON DELETE CASCADE,
+    CONSTRAINT skus_pkey PRIMARY KEY (sku)
+);
+
+CREATE UNIQUE INDEX skus_product_key ON skus (product_id) WHERE variant_id IS NULL;
+CREATE UNIQUE INDEX skus_variant_key ON skus (variant_id) WHERE variant_id IS NOT NULL;
+
+INSERT INTO skus (sku, product_id)
+SELECT sku, id FROM products;
+
+INSERT INTO skus (sku, product_id, variant_id)
+SELECT sku, product_id, id FROM product_variants;
+
+-- +goose StatementBegin
+CREATE FUNCTION skus_sync_product() RETURNS trigger AS $$
+BEGIN
+    IF TG_OP = 'INSERT' THEN
+        INSERT INTO skus (sku, product_id) VALUES (NEW.sku, NEW.id);
+    ELSIF NEW.sku IS DISTINCT FROM OLD.sku THEN
+        UPDATE skus SET sku = NEW.sku
+        WHERE product_id = NEW.id AND variant_id IS NULL;
+    END IF;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE FUNCTION skus_sync_variant() RETURNS trigger AS $$
+BEGIN
+    IF TG_OP = 'INSERT' THEN
+        INSERT INTO skus (sku, product_id, variant_id) VALUES (NEW.sku, NEW.product_id, NEW.id);
+    ELSIF NEW.sku IS DISTINCT FROM OLD.sku THEN
+        UPDATE skus SET sku = NEW.sku
+        WHERE variant_id = NEW.id;
+    END IF;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+-- +goose StatementEnd
+
+CREATE TRIGGER products_skus_sync
+    AFTER INSERT OR UPDATE OF sku ON products
+    FOR EACH ROW EXECUTE FUNCTION skus_sync_product();
+
+CREATE TRIGGER product_variants_skus_sync
+    AFTER INSERT OR UPDATE OF sku ON product_variants
+    FOR EACH ROW EXECUTE FUNCTION skus_sync_variant();
+
+-- +goose Down
+DROP TRIGGER IF EXISTS product_variants_skus_sync ON product_variants;
+DROP TRIGGER IF EXISTS products_skus_sync ON products;
+DROP FUNCTION IF EXISTS skus_sync_variant();
+DROP FUNCTION IF EXISTS skus_sync_product();
+DROP TABLE IF EXISTS skus;
@@ -0,0 +1,5 @@
+-- +goose Up
+CREATE INDEX idx_products_name_id ON products (name, id);
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_products_name_id;
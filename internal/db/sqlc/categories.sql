@@ -0,0 +1,87 @@
+-- name: LockCategoryTree :exec
+-- Serializes writers that derive paths from other categories, so a
+-- concurrent move cannot leave a stale path or create a cycle.
+SELECT pg_advisory_xact_lock(@lock_key::bigint);
+
+
+-- name: CreateCategory :one
+INSERT INTO categories (parent_id, name, slug, path)
+VALUES (@parent_id, @name, @slug, @path)
+RETURNING *;
+
+
+-- name: GetCategoryByID :one
+SELECT * FROM categories
+WHERE id = $1;
+
+
+-- name: ListCategories :many
+SELECT * FROM categories
+ORDER BY path;
+
+
+-- name: UpdateCategory :one
+UPDATE categories
+SET
+    parent_id = @parent_id,
+    name = @name,
+    slug = @slug,
+    path = @path,
+    updated_at = NOW()
+WHERE id = @id
+RETURNING *;
+
+
+-- name: MoveCategoryDescendants :exec
+UPDATE categories
+SET
+    path = @new_path::text || substr(path, length(@old_path::text) + 1),
+    updated_at = NOW()
+WHERE path LIKE @old_path::text || '/%';
+
+
+-- name: TouchCategoryProducts :exec
+-- Product responses embed the name and path of their categories, so
+-- renaming or moving a category changes every product in its subtree.
+UPDATE products
+SET
+    version = version + 1,
+    updated_at = NOW()
+WHERE id IN (
+    SELECT pc.product_id
+    FROM product_categories pc
+    JOIN categories c ON c.id = pc.category_id
+    WHERE c.path = @path::text OR c.path LIKE @path::text || '/%'
+);
+
+
+-- name: DeleteCategory :execrows
+-- The products lose the category, which changes their responses.
+WITH touched AS (
+    UPDATE products p
+    SET
+        version = p.version + 1,
+        updated_at = NOW()
+    WHERE p.id IN (SELECT pc.product_id FROM product_categories pc WHERE pc.category_id = @id::uuid)
+)
+DELETE FROM categories c
+WHERE c.id = @id::uuid;
+
+
+-- name: ClearProductCategories :exec
+DELETE FROM product_categories
+WHERE product_id = $1;
+
+
+-- name: AddProductCategories :exec
+INSERT INTO product_categories (product_id, category_id)
+SELECT @product_id, unnest(@category_ids::uuid[])
+ON CONFLICT DO NOTHING;
+
+
+-- name: ListCategoriesForProducts :many
+SELECT pc.product_id, c.id, c.name, c.slug, c.path
+FROM product_categories pc
+JOIN categories c ON c.id = pc.category_id
+WHERE pc.product_id = ANY(@product_ids::uuid[])
+ORDER BY c.path;
@@ -0,0 +1,19 @@
+-- name: ListExchangeRates :many
+SELECT * FROM exchange_rates
+ORDER BY base_currency, quote_currency;
+
+
+-- name: UpsertExchangeRate :one
+INSERT INTO exchange_rates (base_currency, quote_currency, rate, updated_at, updated_by)
+VALUES ($1, $2, $3, NOW(), $4)
+ON CONFLICT (base_currency, quote_currency) DO UPDATE
+SET
+    rate = EXCLUDED.rate,
+    updated_at = EXCLUDED.updated_at,
+    updated_by = EXCLUDED.updated_by
+RETURNING *;
+
+
+-- name: DeleteExchangeRate :execrows
+DELETE FROM exchange_rates
+WHERE base_currency = $1 AND quote_currency = $2;
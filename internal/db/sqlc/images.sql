@@ -0,0 +1,40 @@
+-- name: ListImagesForProducts :many
+SELECT * FROM product_images
+WHERE product_id = ANY(@product_ids::uuid[])
+ORDER BY product_id, position, created_at;
+
+
+-- name: CreateProductImage :one
+INSERT INTO product_images (id, product_id, position, storage_key, thumbnail_key, content_type, size_bytes, width, height)
+VALUES (
+    @id,
+    @product_id,
+    (SELECT COALESCE(MAX(position) + 1, 0) FROM product_images WHERE product_id = @product_id),
+    @storage_key,
+    @thumbnail_key,
+    @content_type,
+    @size_bytes,
+    @width,
+    @height
+)
+RETURNING *;
+
+
+-- name: DeleteProductImage :one
+DELETE FROM product_images
+WHERE id = @id AND product_id = @product_id
+RETURNING *;
+
+
+-- name: ReorderProductImages :execrows
+-- ReorderProductImages moves every image of the product to the index of its
+-- id in image_ids.
+UPDATE product_images i
+SET position = o.position - 1
+FROM unnest(@image_ids::uuid[]) WITH ORDINALITY AS o(id, position)
+WHERE i.id = o.id AND i.product_id = @product_id;
+
+
+-- name: CountProductImages :one
+SELECT COUNT(*) FROM product_images
+WHERE product_id = $1;
@@ -0,0 +1,54 @@
+-- name: EnsureStockLevel :exec
+INSERT INTO stock_levels (product_id, warehouse_id, variant_id)
+VALUES (@product_id, @warehouse_id, sqlc.narg('variant_id'))
+ON CONFLICT DO NOTHING;
+
+
+-- name: LockStockLevel :one
+SELECT * FROM stock_levels
+WHERE product_id = @product_id
+    AND warehouse_id = @warehouse_id
+    AND variant_id IS NOT DISTINCT FROM sqlc.narg('variant_id')
+FOR UPDATE;
+
+
+-- name: SetStockLevel :one
+UPDATE stock_levels
+SET
+    on_hand = @on_hand,
+    updated_at = NOW()
+WHERE product_id = @product_id
+    AND warehouse_id = @warehouse_id
+    AND variant_id IS NOT DISTINCT FROM sqlc.narg('variant_id')
+RETURNING *;
+
+
+-- name: ListProductStockLevels :many
+SELECT w.id AS warehouse_id, w.code, w.name, s.variant_id, v.sku AS variant_sku, s.on_hand, s.updated_at
+FROM stock_levels s
+JOIN warehouses w ON w.id = s.warehouse_id
+LEFT JOIN product_variants v ON v.id = s.variant_id
+WHERE s.product_id = $1
+ORDER BY w.code, v.sku NULLS FIRST;
+
+
+-- name: SumStockForProducts :many
+SELECT product_id, SUM(on_hand)::bigint AS on_hand
+FROM stock_levels
+WHERE product_id = ANY(@product_ids::uuid[])
+GROUP BY product_id;
+
+
+-- name: CreateStockMovement :one
+INSERT INTO stock_movements (product_id, warehouse_id, variant_id, kind, quantity, reason, actor_id, balance_after, transfer_id)
+VALUES (@product_id, @warehouse_id, @variant_id, @kind, @quantity, @reason, @actor_id, @balance_after, @transfer_id)
+RETURNING *;
+
+
+-- name: ListStockMovements :many
+SELECT * FROM stock_movements
+WHERE product_id = @product_id
+    AND (sqlc.narg('warehouse_id')::uuid IS NULL OR warehouse_id = sqlc.narg('warehouse_id'))
+    AND (sqlc.narg('variant_id')::uuid IS NULL OR variant_id = sqlc.narg('variant_id'))
+ORDER BY id DESC
+LIMIT @page_size OFFSET @page_offset;
@@ -0,0 +1,34 @@
+-- name: RecordProductPrice :exec
+-- Appends the price unless it is already the latest entry, so writes that
+-- leave the price alone do not add to the history. Run it in the transaction
+-- that wrote the product: the product row lock orders concurrent writers.
+INSERT INTO product_price_history (product_id, price, currency, changed_by, changed_at)
+SELECT @product_id::uuid, @price::numeric, @currency::text, sqlc.narg('changed_by')::uuid, @changed_at::timestamptz
+WHERE NOT EXISTS (
+    SELECT 1
+    FROM (
+        SELECT h.price, h.currency
+        FROM product_price_history h
+        WHERE h.product_id = @product_id::uuid
+        ORDER BY h.changed_at DESC, h.id DESC
+        LIMIT 1
+    ) latest
+    WHERE latest.price = @price::numeric AND latest.currency = @currency::text
+);
+
+-- name: ListProductPriceHistory :many
+-- Newest first. effective_until is when the next entry replaced the price,
+-- NULL for the current one.
+SELECT h.id, h.price, h.currency, h.changed_by, h.changed_at, nxt.changed_at AS effective_until
+FROM product_price_history h
+LEFT JOIN product_price_history nxt ON nxt.id = (
+    SELECT n.id
+    FROM product_price_history n
+    WHERE n.product_id = h.product_id
+        AND (n.changed_at, n.id) > (h.changed_at, h.id)
+    ORDER BY n.changed_at, n.id
+    LIMIT 1
+)
+WHERE h.product_id = @product_id
+ORDER BY h.changed_at DESC, h.id DESC
+LIMIT @page_size OFFSET @page_offset;
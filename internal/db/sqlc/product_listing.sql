@@ -0,0 +1,104 @@
+-- name: ListProducts :many
+-- ListProducts returns a page of products ordered by @sort, one of
+-- created_at, updated_at, price or name with an optional - prefix for
+-- descending order. Each ordering is a branch with a static ORDER BY so
+-- Postgres walks the (column, id) index and stops after @page_size rows;
+-- the branches not chosen by @sort are skipped, and the outer ORDER BY
+-- only sorts the one page. The cursor value of the chosen sort is passed
+-- in the argument of its column type.
+WITH filtered AS NOT MATERIALIZED (
+    SELECT * FROM products
+    WHERE
+        deleted_at IS NULL
+        AND (sqlc.narg('name')::text IS NULL OR name ILIKE '%' || sqlc.narg('name')::text || '%')
+        AND (sqlc.narg('min_price')::numeric IS NULL OR price >= sqlc.narg('min_price')::numeric)
+        AND (sqlc.narg('max_price')::numeric IS NULL OR price <= sqlc.narg('max_price')::numeric)
+        AND (sqlc.narg('posted_by')::uuid IS NULL OR posted_by = sqlc.narg('posted_by')::uuid)
+        AND (sqlc.narg('created_after')::timestamptz IS NULL OR created_at >= sqlc.narg('created_after')::timestamptz)
+        AND (sqlc.narg('created_before')::timestamptz IS NULL OR created_at < sqlc.narg('created_before')::timestamptz)
+        AND (sqlc.narg('updated_after')::timestamptz IS NULL OR updated_at >= sqlc.narg('updated_after')::timestamptz)
+        AND (sqlc.narg('updated_before')::timestamptz IS NULL OR updated_at < sqlc.narg('updated_before')::timestamptz)
+        AND (sqlc.narg('category_id')::uuid IS NULL OR EXISTS (
+            SELECT 1
+            FROM product_categories pc
+            JOIN categories c ON c.id = pc.category_id
+            WHERE pc.product_id = products.id
+                AND (c.id = sqlc.narg('category_id')::uuid
+                    OR (@include_descendants::bool AND c.path LIKE (
+                        SELECT root.path FROM categories root WHERE root.id = sqlc.narg('category_id')::uuid
+                    ) || '/%'))
+        ))
+        AND (COALESCE(cardinality(@tags::text[]), 0) = 0 OR (
+            SELECT COUNT(*)
+            FROM product_tags pt
+            JOIN tags t ON t.id = pt.tag_id
+            WHERE pt.product_id = products.id
+                AND t.name = ANY(@tags::text[])
+        ) >= CASE WHEN @match_all_tags::bool THEN cardinality(@tags::text[]) ELSE 1 END)
+)
+SELECT page.* FROM (
+    (SELECT * FROM filtered f
+    WHERE @sort::text = 'created_at'
+        AND (sqlc.narg('cursor_id')::uuid IS NULL
+            OR (f.created_at, f.id) > (sqlc.narg('cursor_time')::timestamptz, sqlc.narg('cursor_id')::uuid))
+    ORDER BY f.created_at, f.id
+    LIMIT @page_size)
+    UNION ALL
+    (SELECT * FROM filtered f
+    WHERE @sort::text = '-created_at'
+        AND (sqlc.narg('cursor_id')::uuid IS NULL
+            OR (f.created_at, f.id) < (sqlc.narg('cursor_time')::timestamptz, sqlc.narg('cursor_id')::uuid))
+    ORDER BY f.created_at DESC, f.id DESC
+    LIMIT @page_size)
+    UNION ALL
+    (SELECT * FROM filtered f
+    WHERE @sort::text = 'updated_at'
+        AND (sqlc.narg('cursor_id')::uuid IS NULL
+            OR (f.updated_at, f.id) > (sqlc.narg('cursor_time')::timestamptz, sqlc.narg('cursor_id')::uuid))
+    ORDER BY f.updated_at, f.id
+    LIMIT @page_size)
+    UNION ALL
+    (SELECT * FROM filtered f
+    WHERE @sort::text = '-updated_at'
+        AND (sqlc.narg('cursor_id')::uuid IS NULL
+            OR (f.updated_at, f.id) < (sqlc.narg('cursor_time')::timestamptz, sqlc.narg('cursor_id')::uuid))
+    ORDER BY f.updated_at DESC, f.id DESC
+    LIMIT @page_size)
+    UNION ALL
+    (SELECT * FROM filtered f
+    WHERE @sort::text = 'price'
+        AND (sqlc.narg('cursor_id')::uuid IS NULL
+            OR (f.price, f.id) > (sqlc.narg('cursor_price')::numeric, sqlc.narg('cursor_id')::uuid))
+    ORDER BY f.price, f.id
+    LIMIT @page_size)
+    UNION ALL
+    (SELECT * FROM filtered f
+    WHERE @sort::text = '-price'
+        AND (sqlc.narg('cursor_id')::uuid IS NULL
+            OR (f.price, f.id) < (sqlc.narg('cursor_price')::numeric, sqlc.narg('cursor_id')::uuid))
+    ORDER BY f.price DESC, f.id DESC
+    LIMIT @page_size)
+    UNION ALL
+    (SELECT * FROM filtered f
+    WHERE @sort::text = 'name'
+        AND (sqlc.narg('cursor_id')::uuid IS NULL
+            OR (f.name, f.id) > (sqlc.narg('cursor_name')::text, sqlc.narg('cursor_id')::uuid))
+    ORDER BY f.name, f.id
+    LIMIT @page_size)
+    UNION ALL
+    (SELECT * FROM filtered f
+    WHERE @sort::text = '-name'
+        AND (sqlc.narg('cursor_id')::uuid IS NULL
+            OR (f.name, f.id) < (sqlc.narg('cursor_name')::text, sqlc.narg('cursor_id')::uuid))
+    ORDER BY f.name DESC, f.id DESC
+    LIMIT @page_size)
+) page
+ORDER BY
+    CASE @sort::text WHEN 'created_at' THEN page.created_at WHEN 'updated_at' THEN page.updated_at END,
+    CASE @sort::text WHEN '-created_at' THEN page.created_at WHEN '-updated_at' THEN page.updated_at END DESC,
+    CASE @sort::text WHEN 'price' THEN page.price END,
+    CASE @sort::text WHEN '-price' THEN page.price END DESC,
+    CASE @sort::text WHEN 'name' THEN page.name END,
+    CASE @sort::text WHEN '-name' THEN page.name END DESC,
+    CASE WHEN @sort::text LIKE '-%' THEN page.id END DESC,
+    page.id;
@@ -1,36 +1,162 @@
 -- name: CreateProductsFromRequest :one
-INSERT INTO products (id, name, price, created_at, updated_at, posted_by)
-VALUES (
-    gen_random_uuid(),
-    $1,
-    $2,
+-- Without an SKU the product gets one derived from its id.
+INSERT INTO products (id, name, price, created_at, updated_at, posted_by, currency, sku, gtin)
+SELECT
+    new_product.id,
+    @name::text,
+    @price::numeric,
     NOW(),
     NOW(),
-    $3
-
-)
+    @posted_by::uuid,
+    @currency::text,
+    COALESCE(sqlc.narg('sku')::text, 'P-' || upper(replace(new_product.id::text, '-', ''))),
+    sqlc.narg('gtin')::text
+FROM (SELECT gen_random_uuid() AS id) new_product
 RETURNING *;
 
 
 -- name: GetAllProducts :many
-SELECT * FROM products;
+SELECT * FROM products
+WHERE deleted_at IS NULL;
 
 
--- name: DeleteProductByID :exec
-DELETE FROM products
-WHERE id = $1;
+-- name: TrashProductByID :execrows
+UPDATE products
+SET
+    deleted_at = NOW(),
+    deleted_by = @deleted_by,
+    updated_at = NOW(),
+    version = version + 1
+WHERE id = @id
+    AND deleted_at IS NULL
+    AND (sqlc.narg('expected_version')::int IS NULL OR version = sqlc.narg('expected_version')::int);
 
 
 -- name: GetProductByID :one
 SELECT * FROM products
-WHERE id = $1;
+WHERE id = $1
+    AND deleted_at IS NULL;
 
 
 -- name: UpdateProduct :one
 UPDATE products
 SET 
-    name = $2,
-    price = $3,
-    updated_at = NOW()
+    name = @name,
+    price = @price,
+    currency = @currency,
+    sku = COALESCE(sqlc.narg('sku'), sku),
+    gtin = COALESCE(sqlc.narg('gtin'), gtin),
+    updated_at = NOW(),
+    version = version + 1
+WHERE id = @id
+    AND deleted_at IS NULL
+    AND (sqlc.narg('expected_version')::int IS NULL OR version = sqlc.narg('expected_version')::int)
+RETURNING *;
+
+
+-- name: SearchProducts :many
+SELECT
+    id,
+    name,
+    price,
+    created_at,
+    updated_at,
+    posted_by,
+    version,
+    currency,
+    sku,
+    gtin,
+    ts_rank(search_vector, to_tsquery('english', @query::text))::real AS rank,
+    -- the name is HTML-escaped before highlighting, so the <mark> tags are
+    -- the only markup in the snippet
+    ts_headline(
+        'english',
+        replace(replace(replace(replace(replace(name,
+            '&', '&amp;'), '<', '&lt;'), '>', '&gt;'), '"', '&#34;'), '''', '&#39;'),
+        to_tsquery('english', @query::text),
+        'StartSel=<mark>, StopSel=</mark>, HighlightAll=true'
+    )::text AS snippet
+FROM products
+WHERE search_vector @@ to_tsquery('english', @query::text)
+    AND deleted_at IS NULL
+ORDER BY rank DESC, id
+LIMIT @page_size OFFSET @page_offset;
+
+
+-- name: PatchProduct :one
+UPDATE products
+SET
+    name = COALESCE(sqlc.narg('name'), name),
+    price = COALESCE(sqlc.narg('price'), price),
+    currency = COALESCE(sqlc.narg('currency'), currency),
+    sku = COALESCE(sqlc.narg('sku'), sku),
+    gtin = CASE WHEN @clear_gtin::bool THEN NULL ELSE COALESCE(sqlc.narg('gtin'), gtin) END,
+    updated_at = NOW(),
+    version = version + 1
+WHERE id = @id
+    AND deleted_at IS NULL
+    AND (sqlc.narg('expected_version')::int IS NULL OR version = sqlc.narg('expected_version')::int)
+RETURNING *;
+
+
+-- name: GetProductBySKU :one
+-- GetProductBySKU resolves product and variant SKUs alike, a variant SKU
+-- to the product the variant belongs to.
+SELECT * FROM products
+WHERE id = (SELECT s.product_id FROM skus s WHERE s.sku = $1)
+    AND deleted_at IS NULL;
+
+
+-- name: GetTrashedProductByID :one
+SELECT * FROM products
 WHERE id = $1
+    AND deleted_at IS NOT NULL;
+
+
+-- name: ListTrashedProducts :many
+SELECT * FROM products
+WHERE deleted_at IS NOT NULL
+    AND (sqlc.narg('posted_by')::uuid IS NULL OR posted_by = sqlc.narg('posted_by')::uuid)
+ORDER BY deleted_at DESC, id
+LIMIT @page_size OFFSET @page_offset;
+
+
+-- name: RestoreProduct :one
+UPDATE products
+SET
+    deleted_at = NULL,
+    deleted_by = NULL,
+    updated_at = NOW(),
+    version = version + 1
+WHERE id = @id
+    AND deleted_at IS NOT NULL
+    AND (sqlc.narg('expected_version')::int IS NULL OR version = sqlc.narg('expected_version')::int)
 RETURNING *;
+
+
+-- name: PurgeProduct :many
+-- PurgeProduct returns a row per image file of the purged product, with
+-- empty keys when it had none, and no rows when nothing was purged. The
+-- image rows go with the product, the files are for the caller to delete.
+WITH purged AS (
+    DELETE FROM products p
+    WHERE p.id = $1
+        AND p.deleted_at IS NOT NULL
+    RETURNING p.id
+)
+SELECT purged.id, i.storage_key, i.thumbnail_key
+FROM purged
+LEFT JOIN product_images i ON i.product_id = purged.id;
+
+
+-- name: PurgeTrashedProducts :many
+-- PurgeTrashedProducts returns rows like PurgeProduct, for every product
+-- purged.
+WITH purged AS (
+    DELETE FROM products p
+    WHERE p.deleted_at < @trashed_before::timestamptz
+    RETURNING p.id
+)
+SELECT purged.id, i.storage_key, i.thumbnail_key
+FROM purged
+LEFT JOIN product_images i ON i.product_id = purged.id;
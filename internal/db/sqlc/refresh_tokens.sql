@@ -1,7 +1,40 @@
 -- name: CreateRefreshToken :exec
-INSERT  INTO refresh_tokens (token, user_id, created_at, updated_at, expires_at, revoked_at)
-VALUES ($1, $2, $3, $4, $5, $6);
+INSERT  INTO refresh_tokens (token, user_id, created_at, updated_at, expires_at, revoked_at, family_id)
+VALUES ($1, $2, $3, $4, $5, $6, $7);
 
 
 -- name: GetRefreshToken :one
-SELECT * FROM refresh_tokens WHERE token = $1; 
\ No newline at end of file
+SELECT * FROM refresh_tokens WHERE token = $1;
+
+
+-- name: RotateRefreshToken :execrows
+UPDATE refresh_tokens
+SET
+    revoked_at = NOW(),
+    updated_at = NOW(),
+    replaced_by = $2
+WHERE token = $1 AND revoked_at IS NULL;
+
+
+-- name: RevokeRefreshTokenFamily :exec
+UPDATE refresh_tokens
+SET
+    revoked_at = NOW(),
+    updated_at = NOW()
+WHERE family_id = $1 AND revoked_at IS NULL;
+
+
+-- name: RevokeRefreshToken :execrows
+UPDATE refresh_tokens
+SET
+    revoked_at = NOW(),
+    updated_at = NOW()
+WHERE token = $1 AND revoked_at IS NULL;
+
+
+-- name: RevokeAllRefreshTokensForUser :execrows
+UPDATE refresh_tokens
+SET
+    revoked_at = NOW(),
+    updated_at = NOW()
+WHERE user_id = $1 AND revoked_at IS NULL;
@@ -0,0 +1,16 @@
+-- name: GetPermissionsByRole :many
+SELECT permission FROM role_permissions
+WHERE role = $1
+ORDER BY permission;
+
+
+-- name: GetRoleByName :one
+SELECT * FROM roles
+WHERE name = $1;
+
+
+-- name: ListRolePermissions :many
+SELECT roles.name, roles.description, role_permissions.permission
+FROM roles
+LEFT JOIN role_permissions ON role_permissions.role = roles.name
+ORDER BY roles.name, role_permissions.permission;
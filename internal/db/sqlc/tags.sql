@@ -0,0 +1,37 @@
+-- name: EnsureTags :exec
+INSERT INTO tags (name)
+SELECT unnest(@names::text[])
+ON CONFLICT (name) DO NOTHING;
+
+
+-- name: ClearProductTags :exec
+DELETE FROM product_tags
+WHERE product_id = $1;
+
+
+-- name: AddProductTags :exec
+INSERT INTO product_tags (product_id, tag_id)
+SELECT @product_id, t.id
+FROM tags t
+WHERE t.name = ANY(@names::text[])
+ON CONFLICT DO NOTHING;
+
+
+-- name: ListTagsForProducts :many
+SELECT pt.product_id, t.name
+FROM product_tags pt
+JOIN tags t ON t.id = pt.tag_id
+WHERE pt.product_id = ANY(@product_ids::uuid[])
+ORDER BY t.name;
+
+
+-- name: ListTags :many
+-- Tags in use on live products, most used first.
+SELECT t.name, COUNT(*) AS product_count
+FROM tags t
+JOIN product_tags pt ON pt.tag_id = t.id
+JOIN products p ON p.id = pt.product_id AND p.deleted_at IS NULL
+WHERE (sqlc.narg('prefix')::text IS NULL OR t.name LIKE sqlc.narg('prefix')::text || '%')
+GROUP BY t.id, t.name
+ORDER BY product_count DESC, t.name
+LIMIT @page_size OFFSET @page_offset;
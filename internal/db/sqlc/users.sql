@@ -17,4 +17,41 @@ WHERE email = $1;
 
 
 -- name: GetRoleByID :one
-SELECT role FROM users WHERE id = $1;
\ No newline at end of file
+SELECT role FROM users WHERE id = $1 AND disabled_at IS NULL;
+
+-- name: GetUserByID :one
+SELECT * FROM users
+WHERE id = $1;
+
+
+-- name: ListUsers :many
+SELECT * FROM users
+ORDER BY created_at, id
+LIMIT $1 OFFSET $2;
+
+
+-- name: CountUsers :one
+SELECT COUNT(*) FROM users;
+
+
+-- name: UpdateUserRole :one
+UPDATE users
+SET
+    role = $2,
+    updated_at = NOW()
+WHERE id = $1
+RETURNING *;
+
+
+-- name: SetUserDisabledAt :one
+UPDATE users
+SET
+    disabled_at = $2,
+    updated_at = NOW()
+WHERE id = $1
+RETURNING *;
+
+
+-- name: DeleteUserByID :execrows
+DELETE FROM users
+WHERE id = $1;
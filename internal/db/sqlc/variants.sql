@@ -0,0 +1,84 @@
+-- name: ListVariantsForProducts :many
+SELECT
+    v.id,
+    v.product_id,
+    v.sku,
+    v.options,
+    COALESCE(v.price, p.price)::text AS price,
+    COALESCE(v.currency, p.currency)::text AS currency,
+    (v.price IS NOT NULL)::boolean AS price_override,
+    COALESCE(s.on_hand, 0)::bigint AS on_hand,
+    v.created_at,
+    v.updated_at
+FROM product_variants v
+JOIN products p ON p.id = v.product_id
+LEFT JOIN (
+    SELECT variant_id, SUM(on_hand) AS on_hand
+    FROM stock_levels
+    WHERE variant_id IS NOT NULL
+    GROUP BY variant_id
+) s ON s.variant_id = v.id
+WHERE v.product_id = ANY(@product_ids::uuid[])
+ORDER BY v.product_id, v.created_at, v.id;
+
+
+-- name: ShareLockProductVariant :one
+-- Stock movements of a variant hold this lock, so the variant cannot be
+-- deleted while its stock moves.
+SELECT * FROM product_variants
+WHERE id = @id AND product_id = @product_id
+FOR SHARE;
+
+
+-- name: LockProductVariant :one
+SELECT * FROM product_variants
+WHERE id = @id AND product_id = @product_id
+FOR UPDATE;
+
+
+-- name: GetOtherVariantOptions :one
+SELECT options FROM product_variants
+WHERE product_id = @product_id AND id <> @exclude_id
+LIMIT 1;
+
+
+-- name: CreateProductVariant :one
+INSERT INTO product_variants (product_id, sku, options, price, currency)
+VALUES (@product_id, @sku, @options, sqlc.narg('price'), sqlc.narg('currency'))
+RETURNING *;
+
+
+-- name: UpdateProductVariant :one
+UPDATE product_variants
+SET
+    sku = @sku,
+    options = @options,
+    price = sqlc.narg('price'),
+    currency = sqlc.narg('currency'),
+    updated_at = NOW()
+WHERE id = @id AND product_id = @product_id
+RETURNING *;
+
+
+-- name: DeleteProductVariant :execrows
+DELETE FROM product_variants
+WHERE id = @id AND product_id = @product_id;
+
+
+-- name: GetVariantOnHand :one
+SELECT COALESCE(SUM(on_hand), 0)::bigint AS on_hand
+FROM stock_levels
+WHERE variant_id = $1;
+
+
+-- name: TouchProduct :one
+-- TouchProduct bumps the version of a product whose variants changed, so
+-- its ETag changes with them. It also locks the product row for the rest of
+-- the transaction.
+UPDATE products
+SET
+    updated_at = NOW(),
+    version = version + 1
+WHERE id = @id
+    AND deleted_at IS NULL
+RETURNING *;
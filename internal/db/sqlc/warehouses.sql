@@ -0,0 +1,43 @@
+-- name: ListWarehouses :many
+SELECT * FROM warehouses
+ORDER BY code;
+
+
+-- name: GetWarehouseByID :one
+SELECT * FROM warehouses
+WHERE id = $1;
+
+
+-- name: GetDefaultWarehouse :one
+SELECT * FROM warehouses
+WHERE is_default;
+
+
+-- name: CreateWarehouse :one
+INSERT INTO warehouses (code, name, is_default)
+VALUES (@code, @name, @is_default)
+RETURNING *;
+
+
+-- name: UpdateWarehouse :one
+UPDATE warehouses
+SET
+    code = @code,
+    name = @name,
+    is_default = @is_default,
+    updated_at = NOW()
+WHERE id = @id
+RETURNING *;
+
+
+-- name: ClearDefaultWarehouse :exec
+UPDATE warehouses
+SET
+    is_default = false,
+    updated_at = NOW()
+WHERE is_default AND id <> @keep_id;
+
+
+-- name: DeleteWarehouse :execrows
+DELETE FROM warehouses
+WHERE id = $1 AND NOT is_default;
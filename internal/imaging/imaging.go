@@ -0,0 +1,146 @@
+// Package imaging validates uploaded images and makes thumbnails of them
+// with the standard library only.
+package imaging
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"image/draw"
+	"image/gif"
+	"image/jpeg"
+	"image/png"
+	"net/http"
+)
+
+// MaxPixels bounds the decoded size of an image, a small file can still
+// decode to a huge bitmap.
+const MaxPixels = 25_000_000
+
+var (
+	ErrUnsupportedFormat = errors.New("image must be a JPEG, PNG or GIF")
+	ErrTooManyPixels     = errors.New("image must not have more than 25 megapixels")
+	ErrCorrupt           = errors.New("image cannot be decoded")
+)
+
+// Image is a decoded upload.
+type Image struct {
+	// ContentType is sniffed from the data, never taken from the client.
+	ContentType string
+	Width       int
+	Height      int
+	img         image.Image
+}
+
+// Decode sniffs the format of data and decodes it.
+func Decode(data []byte) (*Image, error) {
+	contentType := http.DetectContentType(data)
+	var decode func(*bytes.Reader) (image.Image, error)
+	var decodeConfig func(*bytes.Reader) (image.Config, error)
+	switch contentType {
+	case "image/jpeg":
+		decode = func(r *bytes.Reader) (image.Image, error) { return jpeg.Decode(r) }
+		decodeConfig = func(r *bytes.Reader) (image.Config, error) { return jpeg.DecodeConfig(r) }
+	case "image/png":
+		decode = func(r *bytes.Reader) (image.Image, error) { return png.Decode(r) }
+		decodeConfig = func(r *bytes.Reader) (image.Config, error) { return png.DecodeConfig(r) }
+	case "image/gif":
+		// only the first frame of an animation is kept for the thumbnail
+		decode = func(r *bytes.Reader) (image.Image, error) { return gif.Decode(r) }
+		decodeConfig = func(r *bytes.Reader) (image.Config, error) { return gif.DecodeConfig(r) }
+	default:
+		return nil, ErrUnsupportedFormat
+	}
+
+	cfg, err := decodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, ErrCorrupt
+	}
+	if cfg.Width <= 0 || cfg.Height <= 0 {
+		return nil, ErrCorrupt
+	}
+	if int64(cfg.Width)*int64(cfg.Height) > MaxPixels {
+		return nil, ErrTooManyPixels
+	}
+	img, err := decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, ErrCorrupt
+	}
+	return &Image{ContentType: contentType, Width: cfg.Width, Height: cfg.Height, img: img}, nil
+}
+
+// Extension is the file extension for the format of the image.
+func (im *Image) Extension() string {
+	switch im.ContentType {
+	case "image/jpeg":
+		return ".jpg"
+	case "image/png":
+		return ".png"
+	default:
+		return ".gif"
+	}
+}
+
+// Thumbnail scales the image down to fit a maxSize square, keeping its
+// aspect ratio, and encodes it as JPEG for JPEG images and as PNG otherwise
+// so transparency survives. Images that already fit are only re-encoded.
+// It returns the encoded thumbnail, its content type and file extension.
+func (im *Image) Thumbnail(maxSize int) ([]byte, string, string, error) {
+	w, h := im.Width, im.Height
+	if w > maxSize || h > maxSize {
+		if w >= h {
+			w, h = maxSize, max(1, h*maxSize/w)
+		} else {
+			w, h = max(1, w*maxSize/h), maxSize
+		}
+	}
+	thumb := scaleDown(im.img, w, h)
+
+	var buf bytes.Buffer
+	if im.ContentType == "image/jpeg" {
+		if err := jpeg.Encode(&buf, thumb, &jpeg.Options{Quality: 85}); err != nil {
+			return nil, "", "", err
+		}
+		return buf.Bytes(), "image/jpeg", ".jpg", nil
+	}
+	if err := png.Encode(&buf, thumb); err != nil {
+		return nil, "", "", err
+	}
+	return buf.Bytes(), "image/png", ".png", nil
+}
+
+// scaleDown resizes src to w x h, no larger than src, averaging the source
+// pixels that fall into each destination pixel.
+func scaleDown(src image.Image, w, h int) *image.RGBA {
+	b := src.Bounds()
+	sw, sh := b.Dx(), b.Dy()
+	rgba := image.NewRGBA(image.Rect(0, 0, sw, sh))
+	draw.Draw(rgba, rgba.Bounds(), src, b.Min, draw.Src)
+	if w == sw && h == sh {
+		return rgba
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		y0, y1 := y*sh/h, max((y+1)*sh/h, y*sh/h+1)
+		for x := 0; x < w; x++ {
+			x0, x1 := x*sw/w, max((x+1)*sw/w, x*sw/w+1)
+			var sum [4]uint64
+			for sy := y0; sy < y1; sy++ {
+				off := rgba.PixOffset(x0, sy)
+				for sx := x0; sx < x1; sx++ {
+					for c := range sum {
+						sum[c] += uint64(rgba.Pix[off+c])
+					}
+					off += 4
+				}
+			}
+			n := uint64((y1 - y0) * (x1 - x0))
+			off := dst.PixOffset(x, y)
+			for c := range sum {
+				dst.Pix[off+c] = uint8(sum[c] / n)
+			}
+		}
+	}
+	return dst
+}
@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"github.com/Black-tag/productAPI/internal/database"
+	"github.com/Black-tag/productAPI/internal/permissions"
 	"github.com/golang-jwt/jwt/v5"
 	"github.com/google/uuid"
 )
@@ -56,9 +57,15 @@ func Authenticate(secret string, db *database.Queries) func(http.Handler) http.H
 				http.Error(w, "unable to fetch role", http.StatusUnauthorized)
 				return
 			}
+			perms, err := db.GetPermissionsByRole(r.Context(), role)
+			if err != nil {
+				http.Error(w, "unable to fetch permissions", http.StatusInternalServerError)
+				return
+			}
 			ctx := context.WithValue(r.Context(), "userID", userID)
 			ctx = context.WithValue(ctx, "tokenString", tokenSring)
 			ctx = context.WithValue(ctx, "role", role)
+			ctx = context.WithValue(ctx, "permissions", permissions.NewSet(perms...))
 			next.ServeHTTP(w, r.WithContext(ctx))
 
 		})
@@ -7,11 +7,13 @@ import (
     "net/http"
 )
 
-func CorsMiddleware(next http.Handler) http.Handler {
+// CorsMiddleware allows browsers on origin to call the API.
+func CorsMiddleware(origin string, next http.Handler) http.Handler {
     return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-        w.Header().Set("Access-Control-Allow-Origin", "http://localhost:5173") 
-        w.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
-        w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization")
+        w.Header().Set("Access-Control-Allow-Origin", origin)
+        w.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, PATCH, DELETE, OPTIONS")
+        w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization, If-Match, If-None-Match")
+        w.Header().Set("Access-Control-Expose-Headers", "ETag, Last-Modified")
         if r.Method == http.MethodOptions {
             w.WriteHeader(http.StatusOK)
             return
@@ -0,0 +1,30 @@
+package middleware
+
+import "net/http"
+
+// LimitBody caps request bodies at limit bytes, reading past it fails and
+// makes handlers reject the request. Requests matching one of the exempt
+// ServeMux patterns are passed through as they are, their handlers must
+// set a limit of their own.
+func LimitBody(limit int64, next http.Handler, exempt ...string) http.Handler {
+	exemptions := http.NewServeMux()
+	isExempt := make(map[string]bool, len(exempt))
+	for _, pattern := range exempt {
+		exemptions.Handle(pattern, next)
+		isExempt[pattern] = true
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// the pattern is checked against the set as well, for requests the
+		// mux would only redirect it reports the target path instead
+		if _, pattern := exemptions.Handler(r); isExempt[pattern] {
+			next.ServeHTTP(w, r)
+			return
+		}
+		if r.ContentLength > limit {
+			http.Error(w, "request body too large", http.StatusRequestEntityTooLarge)
+			return
+		}
+		r.Body = http.MaxBytesReader(w, r.Body, limit)
+		next.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,64 @@
+package middleware
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+const testUploadRoute = "POST /api/v1/product/{productID}/images"
+
+// readAll answers 413 when the body limit stops it, like the JSON handlers
+// fail on a body they cannot read.
+func readAll(w http.ResponseWriter, r *http.Request) {
+	if _, err := io.ReadAll(r.Body); err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			http.Error(w, "request body too large", http.StatusRequestEntityTooLarge)
+			return
+		}
+		http.Error(w, "cannot read request", http.StatusBadRequest)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func TestLimitBody(t *testing.T) {
+	const limit = 1 << 10
+	tests := []struct {
+		name        string
+		method      string
+		path        string
+		contentType string
+		size        int
+		chunked     bool
+		want        int
+	}{
+		{name: "small json", method: http.MethodPost, path: "/api/v1/login", contentType: "application/json", size: limit, want: http.StatusNoContent},
+		{name: "large json", method: http.MethodPost, path: "/api/v1/login", contentType: "application/json", size: limit + 1, want: http.StatusRequestEntityTooLarge},
+		{name: "large chunked json", method: http.MethodPost, path: "/api/v1/login", contentType: "application/json", size: limit + 1, chunked: true, want: http.StatusRequestEntityTooLarge},
+		{name: "multipart to a json route", method: http.MethodPost, path: "/api/v1/users", contentType: "multipart/form-data; boundary=x", size: limit + 1, want: http.StatusRequestEntityTooLarge},
+		{name: "chunked multipart to a json route", method: http.MethodPost, path: "/api/v1/users", contentType: "multipart/form-data; boundary=x", size: limit + 1, chunked: true, want: http.StatusRequestEntityTooLarge},
+		{name: "upload route", method: http.MethodPost, path: "/api/v1/product/42/images", contentType: "multipart/form-data; boundary=x", size: 4 * limit, want: http.StatusNoContent},
+		{name: "upload path with another method", method: http.MethodPut, path: "/api/v1/product/42/images", contentType: "multipart/form-data; boundary=x", size: limit + 1, want: http.StatusRequestEntityTooLarge},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := LimitBody(limit, http.HandlerFunc(readAll), testUploadRoute)
+			r := httptest.NewRequest(tt.method, tt.path, strings.NewReader(strings.Repeat("x", tt.size)))
+			r.Header.Set("Content-Type", tt.contentType)
+			if tt.chunked {
+				r.ContentLength = -1
+			}
+			w := httptest.NewRecorder()
+
+			handler.ServeHTTP(w, r)
+			if w.Code != tt.want {
+				t.Errorf("%s %s with %d bytes: status = %d, want %d", tt.method, tt.path, tt.size, w.Code, tt.want)
+			}
+		})
+	}
+}
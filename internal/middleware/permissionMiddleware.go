@@ -0,0 +1,24 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/Black-tag/productAPI/internal/permissions"
+)
+
+// RequirePermission must be chained after Authenticate, it rejects requests
+// whose role was not granted every one of perms.
+func RequirePermission(perms ...permissions.Permission) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			granted := permissions.FromContext(r.Context())
+			for _, p := range perms {
+				if !granted.Has(p) {
+					http.Error(w, "insufficient permissions", http.StatusForbidden)
+					return
+				}
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
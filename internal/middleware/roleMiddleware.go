@@ -0,0 +1,24 @@
+package middleware
+
+import "net/http"
+
+// RequireRole must be chained after Authenticate, it rejects requests whose
+// role in the context is not one of roles.
+func RequireRole(roles ...string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			role, ok := r.Context().Value("role").(string)
+			if !ok {
+				http.Error(w, "role not in context", http.StatusUnauthorized)
+				return
+			}
+			for _, allowed := range roles {
+				if role == allowed {
+					next.ServeHTTP(w, r)
+					return
+				}
+			}
+			http.Error(w, "insufficient permissions", http.StatusForbidden)
+		})
+	}
+}
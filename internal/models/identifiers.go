@@ -0,0 +1,56 @@
+package models
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+)
+
+var (
+	ErrInvalidSKU  = errors.New("sku must be 1 to 64 letters, digits, dots, dashes or underscores, starting with a letter or digit")
+	ErrInvalidGTIN = errors.New("gtin must be a 13 digit EAN-13 or 12 digit UPC-A code")
+	ErrGTINCheck   = errors.New("gtin check digit does not match")
+)
+
+var skuPattern = regexp.MustCompile(`^[A-Z0-9][A-Z0-9._-]{0,63}$`)
+
+// NormalizeSKU trims and uppercases an SKU, SKUs are matched without regard
+// to case.
+func NormalizeSKU(sku string) (string, error) {
+	sku = strings.ToUpper(strings.TrimSpace(sku))
+	if !skuPattern.MatchString(sku) {
+		return "", ErrInvalidSKU
+	}
+	return sku, nil
+}
+
+// NormalizeGTIN validates an EAN-13 or UPC-A code including its check digit
+// and returns it as 13 digits. A UPC-A code is the EAN-13 code with a leading
+// zero, so both spellings of the same product compare equal.
+func NormalizeGTIN(gtin string) (string, error) {
+	gtin = strings.TrimSpace(gtin)
+	if !isDigits(gtin) || (len(gtin) != 12 && len(gtin) != 13) {
+		return "", ErrInvalidGTIN
+	}
+	if len(gtin) == 12 {
+		gtin = "0" + gtin
+	}
+	if gtinCheckDigit(gtin[:12]) != gtin[12] {
+		return "", ErrGTINCheck
+	}
+	return gtin, nil
+}
+
+// gtinCheckDigit computes the GS1 check digit: digits are weighted 3 and 1
+// alternately starting from the rightmost one.
+func gtinCheckDigit(digits string) byte {
+	sum := 0
+	for i := len(digits) - 1; i >= 0; i-- {
+		d := int(digits[i] - '0')
+		if (len(digits)-1-i)%2 == 0 {
+			d *= 3
+		}
+		sum += d
+	}
+	return byte('0' + (10-sum%10)%10)
+}
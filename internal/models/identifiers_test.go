@@ -0,0 +1,107 @@
+package models
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestNormalizeGTIN(t *testing.T) {
+	tests := []struct {
+		name    string
+		gtin    string
+		want    string
+		wantErr error
+	}{
+		{name: "EAN-13", gtin: "4006381333931", want: "4006381333931"},
+		{name: "EAN-13 leading 5", gtin: "5000112637922", want: "5000112637922"},
+		{name: "EAN-13 check digit zero", gtin: "0000000000000", want: "0000000000000"},
+		{name: "EAN-13 surrounding space", gtin: " 5901234123457 ", want: "5901234123457"},
+		{name: "UPC-A padded to 13 digits", gtin: "036000291452", want: "0036000291452"},
+		{name: "UPC-A already padded", gtin: "0036000291452", want: "0036000291452"},
+		{name: "bad EAN-13 check digit", gtin: "4006381333932", wantErr: ErrGTINCheck},
+		{name: "bad UPC-A check digit", gtin: "036000291453", wantErr: ErrGTINCheck},
+		{name: "letter", gtin: "40063813339A1", wantErr: ErrInvalidGTIN},
+		{name: "dashes", gtin: "400-638133393", wantErr: ErrInvalidGTIN},
+		{name: "inner space", gtin: "400638 1333931", wantErr: ErrInvalidGTIN},
+		{name: "too short", gtin: "03600029145", wantErr: ErrInvalidGTIN},
+		{name: "too long", gtin: "40063813339310", wantErr: ErrInvalidGTIN},
+		{name: "EAN-8 length", gtin: "96385074", wantErr: ErrInvalidGTIN},
+		{name: "empty", gtin: "", wantErr: ErrInvalidGTIN},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NormalizeGTIN(tt.gtin)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("NormalizeGTIN(%q) error = %v, want %v", tt.gtin, err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("NormalizeGTIN(%q) error = %v", tt.gtin, err)
+			}
+			if got != tt.want {
+				t.Errorf("NormalizeGTIN(%q) = %q, want %q", tt.gtin, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGTINCheckDigit(t *testing.T) {
+	tests := []struct {
+		digits string
+		want   byte
+	}{
+		{"400638133393", '1'},
+		{"590123412345", '7'},
+		{"003600029145", '2'},
+		{"500011263792", '2'},
+		{"000000000000", '0'},
+	}
+	for _, tt := range tests {
+		if got := gtinCheckDigit(tt.digits); got != tt.want {
+			t.Errorf("gtinCheckDigit(%q) = %q, want %q", tt.digits, got, tt.want)
+		}
+	}
+}
+
+func TestNormalizeSKU(t *testing.T) {
+	tests := []struct {
+		name    string
+		sku     string
+		want    string
+		wantErr bool
+	}{
+		{name: "already normal", sku: "ABC-123", want: "ABC-123"},
+		{name: "lowercase", sku: "abc-123", want: "ABC-123"},
+		{name: "mixed case", sku: "Shoe_Red.42", want: "SHOE_RED.42"},
+		{name: "surrounding whitespace", sku: " \tabc-1\n", want: "ABC-1"},
+		{name: "single character", sku: "x", want: "X"},
+		{name: "64 characters", sku: "A123456789012345678901234567890123456789012345678901234567890123", want: "A123456789012345678901234567890123456789012345678901234567890123"},
+		{name: "65 characters", sku: "A1234567890123456789012345678901234567890123456789012345678901234", wantErr: true},
+		{name: "empty", sku: "", wantErr: true},
+		{name: "only whitespace", sku: "   ", wantErr: true},
+		{name: "leading dash", sku: "-ABC", wantErr: true},
+		{name: "leading dot", sku: ".ABC", wantErr: true},
+		{name: "inner space", sku: "AB C", wantErr: true},
+		{name: "slash", sku: "AB/C", wantErr: true},
+		{name: "non-ASCII letter", sku: "ÄBC", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NormalizeSKU(tt.sku)
+			if tt.wantErr {
+				if !errors.Is(err, ErrInvalidSKU) {
+					t.Fatalf("NormalizeSKU(%q) error = %v, want %v", tt.sku, err, ErrInvalidSKU)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("NormalizeSKU(%q) error = %v", tt.sku, err)
+			}
+			if got != tt.want {
+				t.Errorf("NormalizeSKU(%q) = %q, want %q", tt.sku, got, tt.want)
+			}
+		})
+	}
+}
@@ -33,39 +33,305 @@ type LoginResponse struct {
 	RefreshToken string    `json:"refresh_token"`
 }
 
-type ProductCreationRequest struct {
-	Name  string  `json:"name"`
-	Price float64 `json:"price"`
+type RefreshRequest struct {
+	RefreshToken string `json:"refresh_token"`
 }
 
-type ProductCreationResponse struct {
-	ID        uuid.UUID `json:"id"`
-	Name      string    `json:"name"`
-	Price     string    `json:"price"`
-	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
-	PostedBy  uuid.UUID `json:"posted_by"`
+type RefreshResponse struct {
+	Token        string `json:"token"`
+	RefreshToken string `json:"refresh_token"`
 }
 
-type UpdateProductRequest struct {
+type AdminUserResponse struct {
+	ID         uuid.UUID  `json:"id"`
+	Email      string     `json:"email"`
+	Role       string     `json:"role"`
+	CreatedAt  time.Time  `json:"created_at"`
+	UpdatedAt  time.Time  `json:"updated_at"`
+	DisabledAt *time.Time `json:"disabled_at,omitempty"`
+}
+
+type UserListResponse struct {
+	Users  []AdminUserResponse `json:"users"`
+	Total  int64               `json:"total"`
+	Limit  int32               `json:"limit"`
+	Offset int32               `json:"offset"`
+}
+
+type UpdateUserRoleRequest struct {
+	Role string `json:"role"`
+}
+
+type RoleResponse struct {
+	Name        string   `json:"name"`
+	Description string   `json:"description"`
+	Permissions []string `json:"permissions"`
+}
+
+type ProductCreationRequest struct {
 	Name  string `json:"name"`
-	Price string `json:"price"`
+	Price Money  `json:"price"`
+	// SKU defaults to one derived from the product id.
+	SKU         string      `json:"sku,omitempty" example:"TSHIRT-RED-M"`
+	GTIN        string      `json:"gtin,omitempty" example:"4006381333931"`
+	CategoryIDs []uuid.UUID `json:"category_ids,omitempty"`
+	Tags        []string    `json:"tags,omitempty" example:"summer,sale"`
 }
 
-type UpdatedProductResponse struct {
-	ID        uuid.UUID `json:"id"`
-	Name      string    `json:"name"`
-	Price     string    `json:"price"`
-	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
-	PostedBy  uuid.UUID `json:"posted_by"`
+type UpdateProductRequest struct {
+	Name  string `json:"name"`
+	Price Money  `json:"price"`
+	// SKU and GTIN are kept when left out. Use PATCH to remove a GTIN.
+	SKU  string `json:"sku,omitempty" example:"TSHIRT-RED-M"`
+	GTIN string `json:"gtin,omitempty" example:"4006381333931"`
+	// CategoryIDs and Tags replace the product's categories and tags. Leave
+	// them out to keep them, send an empty list to clear them.
+	CategoryIDs []uuid.UUID `json:"category_ids,omitempty"`
+	Tags        []string    `json:"tags,omitempty" example:"summer,sale"`
 }
 
 type ProductResponse struct {
+	ID         uuid.UUID         `json:"id"`
+	Name       string            `json:"name"`
+	SKU        string            `json:"sku"`
+	GTIN       string            `json:"gtin,omitempty"`
+	Price      Money             `json:"price"`
+	CreatedAt  time.Time         `json:"created_at"`
+	UpdatedAt  time.Time         `json:"updated_at"`
+	PostedBy   uuid.UUID         `json:"posted_by"`
+	Version    int32             `json:"version"`
+	Categories []CategoryRef     `json:"categories"`
+	Tags       []string          `json:"tags"`
+	Variants   []VariantResponse `json:"variants"`
+	Images     []ProductImage    `json:"images"`
+	// OnHand is the stock of the product summed over all warehouses.
+	OnHand int64 `json:"on_hand"`
+	// ConvertedPrice and ExchangeRate are only set when the request asked
+	// for prices in another currency.
+	ConvertedPrice *Money `json:"converted_price,omitempty"`
+	ExchangeRate   string `json:"exchange_rate,omitempty"`
+}
+
+type ProductListResponse struct {
+	Products   []ProductResponse `json:"products"`
+	NextCursor string            `json:"next_cursor,omitempty"`
+}
+
+type ProductSearchResult struct {
+	ProductResponse
+	Rank float32 `json:"rank"`
+	// Snippet is the HTML-escaped name with matches wrapped in <mark> tags,
+	// safe to insert as HTML.
+	Snippet string `json:"snippet" example:"Red &amp; blue <mark>shoe</mark>s"`
+}
+
+type TrashedProductResponse struct {
+	ProductResponse
+	DeletedAt time.Time  `json:"deleted_at"`
+	DeletedBy *uuid.UUID `json:"deleted_by,omitempty"`
+}
+
+type TrashListResponse struct {
+	Products []TrashedProductResponse `json:"products"`
+	Limit    int32                    `json:"limit"`
+	Offset   int32                    `json:"offset"`
+}
+
+type ProductSearchResponse struct {
+	Results []ProductSearchResult `json:"results"`
+	Limit   int32                 `json:"limit"`
+	Offset  int32                 `json:"offset"`
+}
+
+type PriceHistoryEntry struct {
+	Price          Money      `json:"price"`
+	ChangedAt      time.Time  `json:"changed_at"`
+	ChangedBy      *uuid.UUID `json:"changed_by,omitempty"`
+	EffectiveUntil *time.Time `json:"effective_until,omitempty"`
+}
+
+type ProductPriceHistoryResponse struct {
+	Prices []PriceHistoryEntry `json:"prices"`
+	Limit  int32               `json:"limit"`
+	Offset int32               `json:"offset"`
+}
+
+type TagResponse struct {
+	Name         string `json:"name" example:"summer"`
+	ProductCount int64  `json:"product_count"`
+}
+
+type CategoryRequest struct {
+	Name string `json:"name" example:"Phones"`
+	// Slug defaults to one derived from Name.
+	Slug     string     `json:"slug,omitempty" example:"phones"`
+	ParentID *uuid.UUID `json:"parent_id,omitempty"`
+}
+
+type CategoryResponse struct {
+	ID        uuid.UUID  `json:"id"`
+	ParentID  *uuid.UUID `json:"parent_id,omitempty"`
+	Name      string     `json:"name"`
+	Slug      string     `json:"slug"`
+	Path      string     `json:"path" example:"electronics/phones"`
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at"`
+}
+
+// CategoryRef is a category as it appears on a product.
+type CategoryRef struct {
+	ID   uuid.UUID `json:"id"`
+	Name string    `json:"name"`
+	Path string    `json:"path" example:"electronics/phones"`
+}
+
+type ExchangeRateRequest struct {
+	Rate string `json:"rate" example:"0.9234"`
+}
+
+type ExchangeRateResponse struct {
+	Base      string     `json:"base"`
+	Quote     string     `json:"quote"`
+	Rate      string     `json:"rate"`
+	UpdatedAt time.Time  `json:"updated_at"`
+	UpdatedBy *uuid.UUID `json:"updated_by,omitempty"`
+}
+
+type StockMovementRequest struct {
+	Kind string `json:"kind" enums:"receive,sell,adjust,return" example:"receive"`
+	// Quantity is positive for receive, sell and return, sell takes it off
+	// the stock. An adjust adds a signed quantity, e.g. -2 after a stock
+	// count found two units missing.
+	Quantity int32  `json:"quantity" example:"10"`
+	Reason   string `json:"reason,omitempty" example:"delivery 2025-10-13"`
+	// WarehouseID defaults to the default warehouse.
+	WarehouseID *uuid.UUID `json:"warehouse_id,omitempty"`
+	// VariantID is left out for stock of the product itself.
+	VariantID *uuid.UUID `json:"variant_id,omitempty"`
+}
+
+type StockMovementResponse struct {
+	ID          int64      `json:"id"`
+	ProductID   uuid.UUID  `json:"product_id"`
+	WarehouseID uuid.UUID  `json:"warehouse_id"`
+	VariantID   *uuid.UUID `json:"variant_id,omitempty"`
+	Kind        string     `json:"kind"`
+	Quantity    int32      `json:"quantity"`
+	Reason      string     `json:"reason,omitempty"`
+	ActorID     *uuid.UUID `json:"actor_id,omitempty"`
+	// BalanceAfter is the on-hand quantity of the warehouse right after the
+	// movement.
+	BalanceAfter int32 `json:"balance_after"`
+	// TransferID pairs the two movements of a transfer.
+	TransferID *uuid.UUID `json:"transfer_id,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+type StockMovementListResponse struct {
+	Movements []StockMovementResponse `json:"movements"`
+	Limit     int32                   `json:"limit"`
+	Offset    int32                   `json:"offset"`
+}
+
+type StockLevelResponse struct {
+	ProductID  uuid.UUID        `json:"product_id"`
+	OnHand     int64            `json:"on_hand"`
+	Warehouses []WarehouseStock `json:"warehouses"`
+}
+
+type WarehouseStock struct {
+	WarehouseID uuid.UUID `json:"warehouse_id"`
+	Code        string    `json:"code" example:"MAIN"`
+	Name        string    `json:"name"`
+	// VariantID and VariantSKU are only set for stock of a variant.
+	VariantID  *uuid.UUID `json:"variant_id,omitempty"`
+	VariantSKU string     `json:"variant_sku,omitempty"`
+	OnHand     int32      `json:"on_hand"`
+	UpdatedAt  time.Time  `json:"updated_at"`
+}
+
+type StockTransferRequest struct {
+	ProductID       uuid.UUID  `json:"product_id"`
+	VariantID       *uuid.UUID `json:"variant_id,omitempty"`
+	FromWarehouseID uuid.UUID  `json:"from_warehouse_id"`
+	ToWarehouseID   uuid.UUID  `json:"to_warehouse_id"`
+	Quantity        int32      `json:"quantity" example:"5"`
+	Reason          string     `json:"reason,omitempty"`
+}
+
+type StockTransferResponse struct {
+	TransferID uuid.UUID             `json:"transfer_id"`
+	Out        StockMovementResponse `json:"out"`
+	In         StockMovementResponse `json:"in"`
+}
+
+type WarehouseRequest struct {
+	Code string `json:"code" example:"BER1"`
+	Name string `json:"name" example:"Berlin"`
+	// IsDefault makes this the warehouse movements go to when they do not
+	// name one. There is always exactly one default warehouse.
+	IsDefault bool `json:"is_default"`
+}
+
+type WarehouseResponse struct {
 	ID        uuid.UUID `json:"id"`
+	Code      string    `json:"code"`
 	Name      string    `json:"name"`
-	Price     string    `json:"price"`
+	IsDefault bool      `json:"is_default"`
 	CreatedAt time.Time `json:"created_at"`
 	UpdatedAt time.Time `json:"updated_at"`
-	PostedBy  uuid.UUID `json:"posted_by"`
+}
+
+type VariantRequest struct {
+	SKU string `json:"sku" example:"TSHIRT-RED-M"`
+	// Options tell the variants of a product apart. Every variant of a
+	// product has the same option names.
+	Options map[string]string `json:"options"`
+	// Price overrides the price of the product, leave it out to use the
+	// product price.
+	Price *Money `json:"price,omitempty"`
+}
+
+type VariantResponse struct {
+	ID      uuid.UUID         `json:"id"`
+	SKU     string            `json:"sku"`
+	Options map[string]string `json:"options"`
+	// Price is the price of the variant, the product price unless
+	// PriceOverride is set.
+	Price         Money     `json:"price"`
+	PriceOverride bool      `json:"price_override"`
+	OnHand        int64     `json:"on_hand"`
+	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
+}
+
+// ProductImage is an image as it appears on a product, images are listed in
+// display order.
+type ProductImage struct {
+	ID           uuid.UUID `json:"id"`
+	URL          string    `json:"url"`
+	ThumbnailURL string    `json:"thumbnail_url"`
+	ContentType  string    `json:"content_type" example:"image/jpeg"`
+	Width        int32     `json:"width"`
+	Height       int32     `json:"height"`
+	SizeBytes    int64     `json:"size_bytes"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+type ImageOrderRequest struct {
+	// ImageIDs lists every image of the product once, in the new order.
+	ImageIDs []uuid.UUID `json:"image_ids"`
+}
+
+// HealthResponse reports whether the service can take traffic, Status is
+// "ok" only when every check passed.
+type HealthResponse struct {
+	Status string                 `json:"status" example:"ok"`
+	Checks map[string]HealthCheck `json:"checks,omitempty"`
+}
+
+type HealthCheck struct {
+	Status    string  `json:"status" example:"ok"`
+	LatencyMS float64 `json:"latency_ms"`
+	Error     string  `json:"error,omitempty"`
 }
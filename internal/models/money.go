@@ -0,0 +1,208 @@
+package models
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// DefaultCurrency is the currency of prices that do not name one.
+const DefaultCurrency = "USD"
+
+// MaxMoneyMinor is the largest amount products.price NUMERIC(10,2) can hold,
+// 99999999.99, in minor units.
+const MaxMoneyMinor = 9_999_999_999
+
+var (
+	ErrInvalidAmount   = errors.New("amount must be a decimal number with at most 2 fractional digits")
+	ErrNegativeAmount  = errors.New("amount must not be negative")
+	ErrAmountTooLarge  = errors.New("amount must not exceed 99999999.99")
+	ErrInvalidCurrency = errors.New("currency must be a 3 letter ISO 4217 code")
+	ErrConvertOverflow = errors.New("converted amount is too large")
+)
+
+// Money is an exact amount in hundredths of a currency unit. It is encoded
+// in JSON as {"amount": "12.34", "currency": "USD"}; the amount is a string
+// so clients never round-trip prices through binary floating point.
+type Money struct {
+	Minor    int64
+	Currency string
+}
+
+// ParseMoney parses a decimal amount such as "12.3" or "12.30" exactly.
+// Fractional digits beyond the second must be zero, exponents are rejected.
+func ParseMoney(amount, currency string) (Money, error) {
+	m := Money{Currency: strings.ToUpper(currency)}
+
+	amount = strings.TrimSpace(amount)
+	negative := strings.HasPrefix(amount, "-")
+	amount = strings.TrimPrefix(amount, "-")
+
+	whole, frac, _ := strings.Cut(amount, ".")
+	if whole == "" || !isDigits(whole) || !isDigits(frac) {
+		return m, ErrInvalidAmount
+	}
+	if len(frac) > 2 {
+		if strings.Trim(frac[2:], "0") != "" {
+			return m, ErrInvalidAmount
+		}
+		frac = frac[:2]
+	}
+	frac += strings.Repeat("0", 2-len(frac))
+
+	whole = strings.TrimLeft(whole, "0")
+	if len(whole) > 8 {
+		return m, ErrAmountTooLarge
+	}
+	minor, err := strconv.ParseInt(whole+frac, 10, 64)
+	if err != nil {
+		return m, ErrInvalidAmount
+	}
+	if negative {
+		minor = -minor
+	}
+	m.Minor = minor
+	return m, m.Validate()
+}
+
+// Validate checks the amount fits products.price and the currency is a
+// well formed code.
+func (m Money) Validate() error {
+	if m.Minor < 0 {
+		return ErrNegativeAmount
+	}
+	if m.Minor > MaxMoneyMinor {
+		return ErrAmountTooLarge
+	}
+	if len(m.Currency) != 3 || strings.ToUpper(m.Currency) != m.Currency || !isLetters(m.Currency) {
+		return ErrInvalidCurrency
+	}
+	return nil
+}
+
+// Amount formats the amount with exactly two fractional digits, the form
+// Postgres NUMERIC(10,2) accepts and returns.
+func (m Money) Amount() string {
+	sign := ""
+	minor := m.Minor
+	if minor < 0 {
+		sign = "-"
+		minor = -minor
+	}
+	return fmt.Sprintf("%s%d.%02d", sign, minor/100, minor%100)
+}
+
+// Convert returns m in currency to, where rate is the number of units of to
+// per unit of m.Currency. The exact product is rounded to the nearest minor
+// unit with halves rounded away from zero, so 0.125 becomes 0.13 and -0.125
+// becomes -0.13. It fails with ErrConvertOverflow when the result does not
+// fit in an int64 of minor units.
+func (m Money) Convert(to string, rate *big.Rat) (Money, error) {
+	exact := new(big.Rat).Mul(new(big.Rat).SetInt64(m.Minor), rate)
+	num, den := exact.Num(), exact.Denom()
+
+	quo, rem := new(big.Int).QuoRem(num, den, new(big.Int))
+	twiceRem := new(big.Int).Mul(rem.Abs(rem), big.NewInt(2))
+	if twiceRem.Cmp(den) >= 0 {
+		if num.Sign() < 0 {
+			quo.Sub(quo, big.NewInt(1))
+		} else {
+			quo.Add(quo, big.NewInt(1))
+		}
+	}
+	if !quo.IsInt64() {
+		return Money{}, ErrConvertOverflow
+	}
+	return Money{Minor: quo.Int64(), Currency: to}, nil
+}
+
+func (m Money) String() string {
+	return m.Amount() + " " + m.Currency
+}
+
+// MoneyJSON is the wire form of Money.
+type MoneyJSON struct {
+	Amount   string `json:"amount" example:"12.34"`
+	Currency string `json:"currency" example:"USD"`
+}
+
+func (m Money) MarshalJSON() ([]byte, error) {
+	return json.Marshal(MoneyJSON{Amount: m.Amount(), Currency: m.Currency})
+}
+
+// UnmarshalJSON accepts the object form as well as a bare JSON number or
+// numeric string, which is taken to be in DefaultCurrency. Numbers are read
+// from their literal text, never through float64.
+func (m *Money) UnmarshalJSON(data []byte) error {
+	data = bytes.TrimSpace(data)
+	if len(data) > 0 && data[0] == '{' {
+		var obj struct {
+			Amount   json.RawMessage `json:"amount"`
+			Currency string          `json:"currency"`
+		}
+		if err := json.Unmarshal(data, &obj); err != nil {
+			return err
+		}
+		if obj.Amount == nil {
+			return ErrInvalidAmount
+		}
+		amount, err := amountLiteral(obj.Amount)
+		if err != nil {
+			return err
+		}
+		if obj.Currency == "" {
+			obj.Currency = DefaultCurrency
+		}
+		parsed, err := ParseMoney(amount, obj.Currency)
+		if err != nil {
+			return err
+		}
+		*m = parsed
+		return nil
+	}
+
+	amount, err := amountLiteral(data)
+	if err != nil {
+		return err
+	}
+	parsed, err := ParseMoney(amount, DefaultCurrency)
+	if err != nil {
+		return err
+	}
+	*m = parsed
+	return nil
+}
+
+func amountLiteral(raw json.RawMessage) (string, error) {
+	var text string
+	if err := json.Unmarshal(raw, &text); err == nil {
+		return text, nil
+	}
+	var number json.Number
+	if err := json.Unmarshal(raw, &number); err != nil {
+		return "", ErrInvalidAmount
+	}
+	return number.String(), nil
+}
+
+func isDigits(s string) bool {
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+func isLetters(s string) bool {
+	for _, r := range s {
+		if r < 'A' || r > 'Z' {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,137 @@
+package models
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+)
+
+func TestParseMoney(t *testing.T) {
+	tests := []struct {
+		name     string
+		amount   string
+		currency string
+		want     Money
+		wantErr  error
+	}{
+		{name: "whole", amount: "12", currency: "USD", want: Money{Minor: 1200, Currency: "USD"}},
+		{name: "one fractional digit", amount: "12.3", currency: "USD", want: Money{Minor: 1230, Currency: "USD"}},
+		{name: "two fractional digits", amount: "12.34", currency: "USD", want: Money{Minor: 1234, Currency: "USD"}},
+		{name: "trailing zeros", amount: "12.3400", currency: "USD", want: Money{Minor: 1234, Currency: "USD"}},
+		{name: "trailing dot", amount: "5.", currency: "USD", want: Money{Minor: 500, Currency: "USD"}},
+		{name: "leading zeros", amount: "0012.50", currency: "USD", want: Money{Minor: 1250, Currency: "USD"}},
+		{name: "smallest unit", amount: "0.01", currency: "USD", want: Money{Minor: 1, Currency: "USD"}},
+		{name: "zero", amount: "0", currency: "USD", want: Money{Minor: 0, Currency: "USD"}},
+		{name: "negative zero", amount: "-0.00", currency: "USD", want: Money{Minor: 0, Currency: "USD"}},
+		{name: "surrounding space", amount: " 7.5 ", currency: "USD", want: Money{Minor: 750, Currency: "USD"}},
+		{name: "currency uppercased", amount: "1", currency: "eur", want: Money{Minor: 100, Currency: "EUR"}},
+		{name: "largest", amount: "99999999.99", currency: "USD", want: Money{Minor: MaxMoneyMinor, Currency: "USD"}},
+		{name: "too large", amount: "100000000", currency: "USD", wantErr: ErrAmountTooLarge},
+		{name: "negative", amount: "-1.00", currency: "USD", wantErr: ErrNegativeAmount},
+		{name: "third fractional digit", amount: "12.345", currency: "USD", wantErr: ErrInvalidAmount},
+		{name: "exponent", amount: "1e3", currency: "USD", wantErr: ErrInvalidAmount},
+		{name: "plus sign", amount: "+1", currency: "USD", wantErr: ErrInvalidAmount},
+		{name: "no whole part", amount: ".5", currency: "USD", wantErr: ErrInvalidAmount},
+		{name: "two dots", amount: "1.2.3", currency: "USD", wantErr: ErrInvalidAmount},
+		{name: "empty", amount: "", currency: "USD", wantErr: ErrInvalidAmount},
+		{name: "letters", amount: "abc", currency: "USD", wantErr: ErrInvalidAmount},
+		{name: "short currency", amount: "1", currency: "US", wantErr: ErrInvalidCurrency},
+		{name: "currency with digits", amount: "1", currency: "US1", wantErr: ErrInvalidCurrency},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseMoney(tt.amount, tt.currency)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("ParseMoney(%q) error = %v, want %v", tt.amount, err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseMoney(%q) error = %v", tt.amount, err)
+			}
+			if got != tt.want {
+				t.Errorf("ParseMoney(%q) = %+v, want %+v", tt.amount, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMoneyAmount(t *testing.T) {
+	tests := []struct {
+		minor int64
+		want  string
+	}{
+		{0, "0.00"},
+		{1, "0.01"},
+		{1230, "12.30"},
+		{-5, "-0.05"},
+		{-1234, "-12.34"},
+	}
+	for _, tt := range tests {
+		if got := (Money{Minor: tt.minor, Currency: "USD"}).Amount(); got != tt.want {
+			t.Errorf("Amount() of %d = %q, want %q", tt.minor, got, tt.want)
+		}
+	}
+}
+
+func TestMoneyConvert(t *testing.T) {
+	tests := []struct {
+		name  string
+		minor int64
+		rate  string
+		want  int64
+	}{
+		{name: "exact", minor: 100, rate: "1.5", want: 150},
+		{name: "zero", minor: 0, rate: "1.2345", want: 0},
+		{name: "below half rounds down", minor: 1, rate: "0.49", want: 0},
+		{name: "above half rounds up", minor: 1, rate: "0.51", want: 1},
+		{name: "exact half rounds away from zero", minor: 1, rate: "0.5", want: 1},
+		{name: "odd exact half rounds away from zero", minor: 3, rate: "0.5", want: 2},
+		{name: "half of a larger amount", minor: 12345, rate: "0.9", want: 11111},
+		{name: "negative below half", minor: -1, rate: "0.49", want: 0},
+		{name: "negative above half", minor: -1, rate: "0.51", want: -1},
+		{name: "negative exact half rounds away from zero", minor: -1, rate: "0.5", want: -1},
+		{name: "negative odd exact half", minor: -3, rate: "0.5", want: -2},
+		{name: "inverse rate", minor: 100, rate: "1/3", want: 33},
+		{name: "inverse rate rounding up", minor: 200, rate: "1/3", want: 67},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rate, ok := new(big.Rat).SetString(tt.rate)
+			if !ok {
+				t.Fatalf("bad rate %q", tt.rate)
+			}
+			got, err := Money{Minor: tt.minor, Currency: "USD"}.Convert("EUR", rate)
+			if err != nil {
+				t.Fatalf("Convert(%d, %s) error = %v", tt.minor, tt.rate, err)
+			}
+			if got.Minor != tt.want || got.Currency != "EUR" {
+				t.Errorf("Convert(%d, %s) = %+v, want {Minor:%d Currency:EUR}", tt.minor, tt.rate, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMoneyConvertOverflow(t *testing.T) {
+	tests := []struct {
+		name string
+		rate string
+	}{
+		// the largest price times the largest NUMERIC(20,10) rate
+		{name: "large rate", rate: "9999999999.9999999999"},
+		// the inverse of the smallest rate
+		{name: "inverse of small rate", rate: "10000000000"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rate, _ := new(big.Rat).SetString(tt.rate)
+			if _, err := (Money{Minor: MaxMoneyMinor, Currency: "USD"}).Convert("EUR", rate); !errors.Is(err, ErrConvertOverflow) {
+				t.Fatalf("Convert error = %v, want %v", err, ErrConvertOverflow)
+			}
+			if _, err := (Money{Minor: -MaxMoneyMinor, Currency: "USD"}).Convert("EUR", rate); !errors.Is(err, ErrConvertOverflow) {
+				t.Fatalf("Convert of negative amount error = %v, want %v", err, ErrConvertOverflow)
+			}
+		})
+	}
+}
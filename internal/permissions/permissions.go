@@ -0,0 +1,60 @@
+package permissions
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+// Permission names match the permission column of the role_permissions table.
+type Permission string
+
+const (
+	ProductCreate    Permission = "product:create"
+	ProductUpdateOwn Permission = "product:update:own"
+	ProductUpdateAny Permission = "product:update:any"
+	ProductDeleteOwn Permission = "product:delete:own"
+	ProductDeleteAny Permission = "product:delete:any"
+	ProductPurge     Permission = "product:purge"
+	UserManage       Permission = "user:manage"
+	CurrencyManage   Permission = "currency:manage"
+	CategoryManage   Permission = "category:manage"
+	InventoryManage  Permission = "inventory:manage"
+	WarehouseManage  Permission = "warehouse:manage"
+)
+
+// Set is the permissions granted to a role.
+type Set map[Permission]struct{}
+
+func NewSet(perms ...string) Set {
+	set := make(Set, len(perms))
+	for _, p := range perms {
+		set[Permission(p)] = struct{}{}
+	}
+	return set
+}
+
+func (s Set) Has(p Permission) bool {
+	_, ok := s[p]
+	return ok
+}
+
+// CanModify is the ownership policy shared by resources that have an owner:
+// the caller needs the "any" permission, or the "own" permission and to be
+// the owner.
+func (s Set) CanModify(callerID, ownerID uuid.UUID, own, any Permission) bool {
+	if s.Has(any) {
+		return true
+	}
+	return callerID == ownerID && s.Has(own)
+}
+
+// FromContext returns the permissions stored by middleware.Authenticate, or
+// an empty set for unauthenticated requests.
+func FromContext(ctx context.Context) Set {
+	set, ok := ctx.Value("permissions").(Set)
+	if !ok {
+		return Set{}
+	}
+	return set
+}
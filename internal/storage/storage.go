@@ -0,0 +1,32 @@
+// Package storage keeps uploaded files such as product images. Handlers only
+// see the Storage interface, so the local filesystem can be swapped for an
+// object store without touching them.
+package storage
+
+import (
+	"context"
+	"errors"
+	"io"
+	"path"
+	"strings"
+)
+
+var ErrInvalidKey = errors.New("invalid storage key")
+
+// Storage stores files under slash separated keys such as
+// "products/<id>/<image>.jpg".
+type Storage interface {
+	// Put stores the content of r under key, replacing any file stored there.
+	Put(ctx context.Context, key string, r io.Reader, contentType string) error
+	// Delete removes the file under key. Deleting a missing file is not an
+	// error.
+	Delete(ctx context.Context, key string) error
+	// URL is where clients can fetch the file under key.
+	URL(key string) string
+}
+
+// validKey reports whether key is a clean relative path, so it cannot
+// escape the storage root.
+func validKey(key string) bool {
+	return key != "" && !strings.HasPrefix(key, "/") && path.Clean(key) == key && key != ".." && !strings.HasPrefix(key, "../")
+}